@@ -0,0 +1,112 @@
+package bitmask
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Concurrency-safe view of a BitMask, all operations are lock-free and use sync/atomic on the underlying words.
+// Works on sliced bitmasks as well, bits outside of the slice are never modified, even if they share the word with it.
+// Mixing atomic and regular (non-atomic) access to the same bits is still a data race.
+type AtomicBitMask struct {
+	bm *BitMask
+}
+
+// Creates new AtomicBitMask of specified length (number of bits). All bits will be cleared.
+func NewAtomic(len uint) *AtomicBitMask {
+	return New(len).Atomic()
+}
+
+// Returns concurrency-safe view of a bitmask, which shares the buffer with it.
+func (bm *BitMask) Atomic() *AtomicBitMask {
+	return &AtomicBitMask{bm: bm}
+}
+
+// Returns the underlying bitmask, which can be used when there's no concurrent access anymore.
+func (a *AtomicBitMask) BitMask() *BitMask {
+	return a.bm
+}
+
+// Returns the legth of bitmask in bits.
+func (a *AtomicBitMask) Len() uint {
+	return a.bm.len
+}
+
+// Atomically sets the bit by bitIndex to 1.
+func (a *AtomicBitMask) Set(bitIndex uint) {
+	a.TestAndSet(bitIndex)
+}
+
+// Atomically clears the bit by bitIndex (sets it to 0).
+func (a *AtomicBitMask) Clear(bitIndex uint) {
+	a.TestAndClear(bitIndex)
+}
+
+// Atomically sets the bit by bitIndex to 1. Returns true if it was already set.
+// Only one of the concurrent callers will get false for the same bit, so it can be used to claim it.
+func (a *AtomicBitMask) TestAndSet(bitIndex uint) bool {
+	ptr, m := a.getBit(bitIndex)
+	return uint(atomic.OrUintptr(ptr, uintptr(m)))&m != 0
+}
+
+// Atomically clears the bit by bitIndex. Returns true if it was set.
+// Only one of the concurrent callers will get true for the same bit, so it can be used to release it.
+func (a *AtomicBitMask) TestAndClear(bitIndex uint) bool {
+	ptr, m := a.getBit(bitIndex)
+	return uint(atomic.AndUintptr(ptr, ^uintptr(m)))&m != 0
+}
+
+// Atomically checks, whether the bit by bitIndex is set or cleared.
+func (a *AtomicBitMask) IsSet(bitIndex uint) bool {
+	ptr, m := a.getBit(bitIndex)
+	return uint(atomic.LoadUintptr(ptr))&m != 0
+}
+
+// Atomically loads uint by index, without reversing the endianness, just like BitMask.UintRaw.
+// Bits, which are not the part of the bitmask (if it's a slice), are cleared.
+func (a *AtomicBitMask) UintRaw(index int) uint {
+	return uint(atomic.LoadUintptr(a.getWord(index))) & a.bm.getStoreWordMask(index)
+}
+
+// Atomically replaces uint by index with new value, if its current value is equal to old, without reversing the endianness.
+// Returns true if the value was replaced. Use UintRaw to get the current value.
+// Only the bits, which are the part of the bitmask (if it's a slice), are compared and replaced.
+func (a *AtomicBitMask) CompareAndSwapWord(index int, old uint, new uint) bool {
+	ptr := a.getWord(index)
+	mask := a.bm.getStoreWordMask(index)
+	for {
+		current := uint(atomic.LoadUintptr(ptr))
+		if current&mask != old&mask {
+			return false
+		}
+		if atomic.CompareAndSwapUintptr(ptr, uintptr(current), uintptr(current&^mask|new&mask)) {
+			return true
+		}
+		// bits outside of the bitmask were changed concurrently, retry
+	}
+}
+
+// Returns the number of set bits. Each word is loaded atomically, but the result is not a snapshot of the whole bitmask,
+// if there're concurrent modifications.
+func (a *AtomicBitMask) Count() uint {
+	if a.bm.len == 0 {
+		return 0
+	}
+	count := 0
+	for i := 0; i < len(a.bm.store); i++ {
+		count += bits.OnesCount(a.UintRaw(i))
+	}
+	return uint(count)
+}
+
+// uint and uintptr have the same size on all the platforms supported by Go
+func (a *AtomicBitMask) getWord(index int) *uintptr {
+	return (*uintptr)(unsafe.Pointer(&a.bm.store[index]))
+}
+
+func (a *AtomicBitMask) getBit(bitIndex uint) (*uintptr, uint) {
+	checkBounds(a.bm.len, bitIndex)
+	bref, m := a.bm.getBit(bitIndex)
+	return (*uintptr)(unsafe.Pointer(bref)), m
+}
@@ -0,0 +1,132 @@
+package bitmask
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicConcurrentSet(t *testing.T) {
+	n := uint(5*uintSize + 3)
+	base := New(n + 10)
+	a := base.Slice(5, n+5).Atomic()
+	workerCount := 16
+
+	wg := sync.WaitGroup{}
+	wg.Add(workerCount)
+	for workerId := range workerCount {
+		go func() {
+			defer wg.Done()
+			// every worker sets different bits of the same words
+			for i := uint(workerId); i < n; i += uint(workerCount) {
+				a.Set(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, n, a.Count())
+	assert.True(t, a.BitMask().All())
+	// bits outside of the slice are untouched
+	assert.Equal(t, n, base.Count())
+}
+
+func TestAtomicClaim(t *testing.T) {
+	n := uint(3 * uintSize)
+	a := NewAtomic(n)
+	workerCount := 8
+
+	claimed := make([][]uint, workerCount)
+	wg := sync.WaitGroup{}
+	wg.Add(workerCount)
+	for workerId := range workerCount {
+		go func() {
+			defer wg.Done()
+			for i := uint(0); i < n; i++ {
+				if !a.TestAndSet(i) {
+					claimed[workerId] = append(claimed[workerId], i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every bit is claimed exactly once
+	total := 0
+	for _, c := range claimed {
+		total += len(c)
+	}
+	assert.Equal(t, int(n), total)
+
+	released := make([]int, workerCount)
+	wg.Add(workerCount)
+	for workerId := range workerCount {
+		go func() {
+			defer wg.Done()
+			for i := uint(0); i < n; i++ {
+				if a.TestAndClear(i) {
+					released[workerId]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	total = 0
+	for _, r := range released {
+		total += r
+	}
+	assert.Equal(t, int(n), total)
+	assert.Equal(t, uint(0), a.Count())
+}
+
+func TestAtomicCompareAndSwapWord(t *testing.T) {
+	base := NewFromUint(0, 0)
+	a := base.Slice(2, uintSize+2).Atomic()
+	workerCount := 8
+	increments := 1000
+
+	// concurrently toggling bits outside of the slice, which share the word
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	outside := base.Slice(0, 2).Atomic()
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				if outside.TestAndSet(0) {
+					outside.Clear(0)
+				}
+			}
+		}
+	}()
+
+	// using the first word of the slice as a counter
+	wg := sync.WaitGroup{}
+	wg.Add(workerCount)
+	for range workerCount {
+		go func() {
+			defer wg.Done()
+			for range increments {
+				for {
+					old := a.UintRaw(0)
+					if a.CompareAndSwapWord(0, old, old+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	<-stopped
+
+	assert.Equal(t, uint(workerCount*increments), a.UintRaw(0))
+	assert.False(t, a.CompareAndSwapWord(0, 0, 1))
+	assert.False(t, a.IsSet(0))
+	assert.False(t, base.IsSet(1))
+}
@@ -0,0 +1,38 @@
+package bitmask
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// Creates a bitmask of len bits from a non-negative integer, so that bit i is set if x has the bit 2^i
+// (the same order as in NewFromUint). Higher bits of x, which don't fit into len, are ignored.
+func NewFromBigInt(x *big.Int, len uint) *BitMask {
+	if x.Sign() < 0 {
+		panic(fmt.Sprintf("negative integer %v can't be converted to bitmask", x))
+	}
+	bm := New(len)
+	for i, w := range x.Bits() {
+		if uint(i) >= uint(bm.LenUint()) {
+			break
+		}
+		bm.store[i] = bits.Reverse(uint(w))
+	}
+	if len > 0 {
+		// clear the bits after len
+		bm.store[bm.LenUint()-1] &= bm.getStoreWordMask(bm.LenUint() - 1)
+	}
+	return bm
+}
+
+// Returns a new non-negative integer, which has the bit 2^i if the bit i of bitmask is set (see NewFromBigInt).
+func (bm *BitMask) BigInt() *big.Int {
+	words := make([]big.Word, bm.LenWords())
+	i := 0
+	for w := range bm.Words() {
+		words[i] = big.Word(w)
+		i++
+	}
+	return new(big.Int).SetBits(words)
+}
@@ -0,0 +1,60 @@
+package bitmask
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromBigInt(t *testing.T) {
+	x, _ := new(big.Int).SetString("10000000000000000000000000000000000000000000000000000000000000000000000101", 2)
+
+	bm := NewFromBigInt(x, 80)
+	assert.Equal(t, uint(80), bm.Len())
+	assert.Equal(t, []uint{0, 2, 73}, sortedSetBits(bm))
+
+	// higher bits are ignored
+	assert.Equal(t, []uint{0, 2}, sortedSetBits(NewFromBigInt(x, 73)))
+	assert.Equal(t, []uint{0}, sortedSetBits(NewFromBigInt(x, 1)))
+	assert.Equal(t, uint(0), NewFromBigInt(x, 0).Len())
+	assert.Equal(t, uint(0), NewFromBigInt(big.NewInt(0), 100).Count())
+
+	assert.Equal(t, bitString(NewFromUint(5)), bitString(NewFromBigInt(big.NewInt(5), uintSize)))
+	assert.Panics(t, func() { NewFromBigInt(big.NewInt(-1), 10) })
+}
+
+func TestBigInt(t *testing.T) {
+	assert.Equal(t, int64(0), New(0).BigInt().Int64())
+	assert.Equal(t, int64(0), New(100).BigInt().Int64())
+	assert.Equal(t, int64(5), NewFromUint(5).BigInt().Int64())
+
+	bm := New(100)
+	bm.Set(1)
+	bm.Set(99)
+	expected := new(big.Int).Lsh(big.NewInt(1), 99)
+	expected.SetBit(expected, 1, 1)
+	assert.Equal(t, expected, bm.BigInt())
+
+	// sliced view
+	assert.Equal(t, big.NewInt(1<<30), bm.Slice(69, 100).BigInt())
+	assert.Equal(t, big.NewInt(1), bm.Slice(1, 99).BigInt())
+}
+
+func TestBigIntRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 200 {
+		bm, _ := randomSlice(rnd, 100, uint(rnd.Intn(200)))
+
+		x := bm.BigInt()
+		for i := range bm.Len() + 10 {
+			assert.Equal(t, i < bm.Len() && bm.IsSet(i), x.Bit(int(i)) == 1)
+		}
+		assert.Equal(t, bitString(bm), bitString(NewFromBigInt(x, bm.Len())))
+
+		// arithmetic
+		sum := new(big.Int).Add(x, big.NewInt(1))
+		assert.Equal(t, 0, sum.Cmp(NewFromBigInt(sum, bm.Len()+1).BigInt()))
+	}
+}
@@ -0,0 +1,281 @@
+package bitmask
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// whole bytes are flushed to io.Writer, when the buffer exceeds this size
+const bitWriterBufferBits = 32 * 1024
+
+// bytes are read from io.Reader by chunks of this size
+const bitReaderChunkBytes = 512
+
+// Order of bits in a stream, or in bytes (see NewFromBytes).
+type BitOrder int
+
+const (
+	// The most significant bit of a value goes first, and the first bit of a stream is the most significant bit of a byte (0x80).
+	MSBFirst BitOrder = iota
+	// The least significant bit of a value goes first, and the first bit of a stream is the least significant bit of a byte (0x01).
+	LSBFirst
+)
+
+// Writes a stream of bits, either to the end of a growing BitMask, or to an io.Writer.
+type BitWriter struct {
+	// target bitmask, or the buffer of bits which are not flushed yet to w
+	bm    *BitMask
+	order BitOrder
+	w     io.Writer
+	// total number of bits written
+	written uint
+	err     error
+}
+
+// Creates new writer, which appends bits to the end of bm (see Resize about sharing the memory with other slices).
+func NewBitWriter(bm *BitMask, order BitOrder) *BitWriter {
+	return &BitWriter{bm: bm, order: order}
+}
+
+// Creates new writer, which packs bits into bytes and writes them to w.
+// Whole bytes are written to w, when the internal buffer is full, and on Flush or Close.
+// Write errors are sticky: the first one is returned by Flush and Close, and all the following writes are discarded.
+func NewBitWriterTo(w io.Writer, order BitOrder) *BitWriter {
+	return &BitWriter{bm: New(0), order: order, w: w}
+}
+
+// Returns the total number of bits written.
+func (bw *BitWriter) Len() uint {
+	return bw.written
+}
+
+// Writes n (up to 64) least significant bits of value, in the order of the writer.
+func (bw *BitWriter) WriteBits(value uint64, n uint) {
+	if n > 64 {
+		panic(fmt.Sprintf("can't write %v bits at once, maximum is 64", n))
+	}
+	if n == 0 || bw.err != nil {
+		return
+	}
+	// bits in the order of the stream, starting from the most significant one
+	stream := value << (64 - n)
+	if bw.order == LSBFirst {
+		stream = bits.Reverse64(value)
+	}
+	bitIndex := bw.bm.len
+	bw.bm.Resize(bitIndex + n)
+	bw.bm.storeStream(bitIndex, n, stream)
+	bw.written += n
+	if bw.w != nil && bw.bm.len >= bitWriterBufferBits {
+		bw.Flush()
+	}
+}
+
+// Writes a single bit.
+func (bw *BitWriter) WriteBit(isSet bool) {
+	if isSet {
+		bw.WriteBits(1, 1)
+	} else {
+		bw.WriteBits(0, 1)
+	}
+}
+
+// Writes n in unary code: n zeros followed by a one.
+func (bw *BitWriter) WriteUnary(n uint) {
+	for ; n > 64; n -= 64 {
+		bw.WriteBits(0, 64)
+	}
+	bw.WriteBits(0, n)
+	bw.WriteBit(true)
+}
+
+// Writes value in order-0 Exp-Golomb code: value+1 has k+1 significant bits, which are written as k zeros,
+// followed by a one (the most significant bit of value+1) and k remaining bits of value+1 in the order of the writer.
+func (bw *BitWriter) WriteExpGolomb(value uint64) {
+	x := value + 1
+	k := uint(bits.Len64(x)) - 1
+	if x == 0 {
+		// value+1 overflows, it's 1<<64
+		k = 64
+	}
+	bw.WriteUnary(k)
+	bw.WriteBits(x, k)
+}
+
+// Pads the stream with zeros to the byte boundary.
+func (bw *BitWriter) Align() {
+	bw.WriteBits(0, (8-bw.Len()%8)%8)
+}
+
+// Writes all whole bytes to the underlying io.Writer, the remaining bits are kept in the buffer.
+// Does nothing for the writer to BitMask.
+func (bw *BitWriter) Flush() error {
+	if bw.w == nil || bw.err != nil {
+		return bw.err
+	}
+	bytesN := bw.bm.len / 8
+	if bytesN == 0 {
+		return nil
+	}
+	data := bw.bm.Slice(0, bytesN*8).appendBytes(nil, bw.order)
+	if _, err := bw.w.Write(data); err != nil {
+		bw.err = err
+		bw.bm.Resize(0)
+		return err
+	}
+	rest := bw.bm.len - bytesN*8
+	Copy(bw.bm, bw.bm.Slice(bytesN*8, bw.bm.len))
+	bw.bm.Resize(rest)
+	return nil
+}
+
+// Pads the stream with zeros to the byte boundary and flushes it. Doesn't close the underlying io.Writer.
+func (bw *BitWriter) Close() error {
+	bw.Align()
+	return bw.Flush()
+}
+
+// Reads a stream of bits, either from a BitMask, or from an io.Reader.
+//
+// When there are not enough bits left, read methods return io.EOF if the stream has ended at the start of the value,
+// and io.ErrUnexpectedEOF otherwise. ReadBits doesn't consume any bits in this case.
+// Note, that the stream, read from io.Reader, may end with padding bits of the last byte.
+type BitReader struct {
+	// bits which are not consumed yet start from pos
+	bm    *BitMask
+	pos   uint
+	order BitOrder
+	r     io.Reader
+	// number of bits, which were consumed and dropped from bm
+	dropped uint
+}
+
+// Creates new reader of bm bits.
+func NewBitReader(bm *BitMask, order BitOrder) *BitReader {
+	return &BitReader{bm: bm, order: order}
+}
+
+// Creates new reader of bits, packed into bytes, which are read from r.
+// Reader is buffered, so it may read more bytes from r than necessary.
+func NewBitReaderFrom(r io.Reader, order BitOrder) *BitReader {
+	return &BitReader{bm: New(0), order: order, r: r}
+}
+
+// Returns the total number of bits read.
+func (br *BitReader) Pos() uint {
+	return br.dropped + br.pos
+}
+
+// Reads n (up to 64) bits, and returns them as the least significant bits of value, in the order of the reader.
+func (br *BitReader) ReadBits(n uint) (value uint64, err error) {
+	if n > 64 {
+		panic(fmt.Sprintf("can't read %v bits at once, maximum is 64", n))
+	}
+	if err := br.fill(n); err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	// bits in the order of the stream, starting from the most significant one
+	stream := br.bm.loadStream(br.pos, n)
+	br.pos += n
+	if br.order == LSBFirst {
+		return bits.Reverse64(stream), nil
+	}
+	return stream >> (64 - n), nil
+}
+
+// Reads a single bit.
+func (br *BitReader) ReadBit() (bool, error) {
+	bit, err := br.ReadBits(1)
+	return bit == 1, err
+}
+
+// Reads a value in unary code: number of zeros followed by a one.
+func (br *BitReader) ReadUnary() (uint, error) {
+	n := uint(0)
+	for {
+		if err := br.fill(1); err != nil {
+			if n > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		available := minUint(uintSize, br.bm.len-br.pos)
+		// bits after the end are zero
+		w := br.bm.loadWord(br.pos)
+		if w == 0 {
+			n += available
+			br.pos += available
+			continue
+		}
+		zeros := uint(bits.LeadingZeros(w))
+		n += zeros
+		br.pos += zeros + 1
+		return n, nil
+	}
+}
+
+// Reads a value in order-0 Exp-Golomb code (see BitWriter.WriteExpGolomb).
+// Returns ErrInvalidEncoding if the value doesn't fit into uint64.
+func (br *BitReader) ReadExpGolomb() (uint64, error) {
+	k, err := br.ReadUnary()
+	if err != nil {
+		return 0, err
+	}
+	if k > 64 {
+		return 0, fmt.Errorf("%w: exp-golomb code of %v bits is too long", ErrInvalidEncoding, 2*k+1)
+	}
+	rest, err := br.ReadBits(k)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return 0, err
+	}
+	if k == 64 {
+		if rest != 0 {
+			return 0, fmt.Errorf("%w: exp-golomb value doesn't fit into 64 bits", ErrInvalidEncoding)
+		}
+		return 1<<64 - 1, nil
+	}
+	return (1<<k | rest) - 1, nil
+}
+
+// Skips bits up to the byte boundary.
+func (br *BitReader) Align() error {
+	_, err := br.ReadBits((8 - br.Pos()%8) % 8)
+	return err
+}
+
+// makes sure that at least n bits are available after pos
+func (br *BitReader) fill(n uint) error {
+	if br.bm.len-br.pos >= n {
+		return nil
+	}
+	if br.r != nil {
+		// drop consumed bits
+		rest := br.bm.len - br.pos
+		Copy(br.bm, br.bm.Slice(br.pos, br.bm.len))
+		br.bm.Resize(rest)
+		br.dropped += br.pos
+		br.pos = 0
+
+		missingBytes := int((n - rest + 7) / 8)
+		buf := make([]byte, max(missingBytes, bitReaderChunkBytes))
+		readN, err := io.ReadAtLeast(br.r, buf, missingBytes)
+		br.bm.AppendMask(newFromBytes(buf[:readN], uint(readN)*8, br.order))
+		if br.bm.len-br.pos >= n {
+			return nil
+		}
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+	}
+	if br.bm.len == br.pos {
+		return io.EOF
+	}
+	return io.ErrUnexpectedEOF
+}
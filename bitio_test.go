@@ -0,0 +1,240 @@
+package bitmask
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitWriter(t *testing.T) {
+	tests := map[string]struct {
+		order    BitOrder
+		expected string
+	}{
+		"msb": {MSBFirst, "1" + "101" + "101" + strings.Repeat("0", 64) + "0001" + "00101"},
+		"lsb": {LSBFirst, "1" + "101" + "101" + strings.Repeat("0", 64) + "0001" + "00110"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bm := New(1)
+			bm.Set(0)
+			w := NewBitWriter(bm, test.order)
+			w.WriteBits(0b1101, 3)
+			w.WriteBits(0b101, 3)
+			w.WriteBits(0, 64)
+			w.WriteUnary(3)
+			w.WriteExpGolomb(4)
+			assert.NoError(t, w.Flush())
+			assert.Equal(t, uint(79), w.Len())
+			assert.Equal(t, test.expected, bitString(bm))
+		})
+	}
+}
+
+func TestBitWriterTo(t *testing.T) {
+	tests := map[string]struct {
+		order    BitOrder
+		expected []byte
+	}{
+		"msb": {MSBFirst, []byte{0b10110000, 0b11110000}},
+		"lsb": {LSBFirst, []byte{0b11111011, 0b00000000}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := NewBitWriterTo(buf, test.order)
+			w.WriteBits(0b1011, 4)
+			w.WriteBits(0b1111, 8)
+			assert.NoError(t, w.Flush())
+			assert.Equal(t, test.expected[:1], buf.Bytes())
+			assert.NoError(t, w.Close())
+			assert.Equal(t, test.expected, buf.Bytes())
+			assert.Equal(t, uint(16), w.Len())
+		})
+	}
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestBitWriterStickyError(t *testing.T) {
+	expectedErr := errors.New("test")
+	w := NewBitWriterTo(failingWriter{expectedErr}, MSBFirst)
+	for range bitWriterBufferBits / 64 {
+		w.WriteBits(math.MaxUint64, 64)
+	}
+	w.WriteBits(1, 8)
+	assert.Equal(t, expectedErr, w.Close())
+	assert.Equal(t, uint(0), w.bm.Len())
+}
+
+func TestExpGolomb(t *testing.T) {
+	values := map[uint64]string{
+		0: "1",
+		1: "010",
+		2: "011",
+		3: "00100",
+		6: "00111",
+		7: "0001000",
+	}
+	for value, expected := range values {
+		bm := New(0)
+		NewBitWriter(bm, MSBFirst).WriteExpGolomb(value)
+		assert.Equal(t, expected, bitString(bm))
+
+		decoded, err := NewBitReader(bm, MSBFirst).ReadExpGolomb()
+		assert.NoError(t, err)
+		assert.Equal(t, value, decoded)
+	}
+}
+
+func TestExpGolombInvalid(t *testing.T) {
+	bm := New(0)
+	w := NewBitWriter(bm, MSBFirst)
+	w.WriteUnary(65)
+	_, err := NewBitReader(bm, MSBFirst).ReadExpGolomb()
+	assert.True(t, errors.Is(err, ErrInvalidEncoding))
+
+	bm = New(0)
+	w = NewBitWriter(bm, MSBFirst)
+	w.WriteUnary(64)
+	w.WriteBits(1, 64)
+	_, err = NewBitReader(bm, MSBFirst).ReadExpGolomb()
+	assert.True(t, errors.Is(err, ErrInvalidEncoding))
+}
+
+func TestBitReaderEOF(t *testing.T) {
+	bm := NewFromUint(0b1010).Slice(0, 4)
+	r := NewBitReader(bm, LSBFirst)
+
+	_, err := r.ReadBits(8)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, uint(0), r.Pos())
+
+	value, err := r.ReadBits(4)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0b1010), value)
+
+	_, err = r.ReadBit()
+	assert.Equal(t, io.EOF, err)
+	_, err = r.ReadUnary()
+	assert.Equal(t, io.EOF, err)
+	_, err = r.ReadExpGolomb()
+	assert.Equal(t, io.EOF, err)
+
+	r = NewBitReader(New(100), MSBFirst)
+	_, err = r.ReadUnary()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	bm = New(2)
+	bm.Set(1)
+	_, err = NewBitReader(bm, MSBFirst).ReadExpGolomb()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestBitReaderFromError(t *testing.T) {
+	expectedErr := errors.New("test")
+	r := NewBitReaderFrom(io.MultiReader(bytes.NewReader([]byte{0xff}), iotest.ErrReader(expectedErr)), MSBFirst)
+	value, err := r.ReadBits(8)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0xff), value)
+	_, err = r.ReadBits(8)
+	assert.Equal(t, expectedErr, err)
+}
+
+type bitOp struct {
+	kind  int
+	value uint64
+	n     uint
+}
+
+func TestBitStreamRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		ops := make([]bitOp, 5000)
+		for i := range ops {
+			op := bitOp{kind: rnd.Intn(5)}
+			switch op.kind {
+			case 0:
+				op.n = uint(rnd.Intn(65))
+				op.value = rnd.Uint64() & (math.MaxUint64 >> (64 - op.n))
+			case 1:
+				op.n = 1
+				op.value = uint64(rnd.Intn(2))
+			case 2:
+				op.value = uint64(rnd.Intn(200))
+			case 3:
+				op.value = rnd.Uint64() >> rnd.Intn(65)
+			case 4:
+				// byte alignment
+			}
+			ops[i] = op
+		}
+
+		bm := New(0)
+		buf := &bytes.Buffer{}
+		writers := []*BitWriter{NewBitWriter(bm, order), NewBitWriterTo(buf, order)}
+		for _, w := range writers {
+			for _, op := range ops {
+				switch op.kind {
+				case 0:
+					w.WriteBits(op.value, op.n)
+				case 1:
+					w.WriteBit(op.value == 1)
+				case 2:
+					w.WriteUnary(uint(op.value))
+				case 3:
+					w.WriteExpGolomb(op.value)
+				case 4:
+					w.Align()
+				}
+			}
+			assert.NoError(t, w.Close())
+		}
+		assert.Equal(t, bm.appendBytes(nil, order), buf.Bytes())
+
+		readers := []*BitReader{NewBitReader(bm, order), NewBitReaderFrom(iotest.OneByteReader(bytes.NewReader(buf.Bytes())), order)}
+		for _, r := range readers {
+			for i, op := range ops {
+				var value uint64
+				var err error
+				switch op.kind {
+				case 0:
+					value, err = r.ReadBits(op.n)
+				case 1:
+					var isSet bool
+					isSet, err = r.ReadBit()
+					if isSet {
+						value = 1
+					}
+				case 2:
+					var n uint
+					n, err = r.ReadUnary()
+					value = uint64(n)
+				case 3:
+					value, err = r.ReadExpGolomb()
+				case 4:
+					err = r.Align()
+				}
+				if !assert.NoError(t, err) || !assert.Equal(t, op.value, value, "op %v", i) {
+					return
+				}
+			}
+			assert.NoError(t, r.Align())
+			_, err := r.ReadBit()
+			assert.Equal(t, io.EOF, err)
+		}
+	}
+}
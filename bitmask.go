@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"iter"
 	"math/bits"
-	"reflect"
 	"strings"
 	"unsafe"
 )
@@ -53,7 +52,7 @@ func NewFromUintRawNocopy(values ...uint) *BitMask {
 	return &BitMask{store: values, len: uintSize * uint(len(values))}
 }
 
-// Returns the legth of bitmask in bits. It will never be changed for the given receiver.
+// Returns the legth of bitmask in bits. It's only changed by Resize, AppendBit, AppendMask and unmarshaling.
 func (bm *BitMask) Len() uint {
 	return bm.len
 }
@@ -64,15 +63,46 @@ func (bm *BitMask) LenUint() int {
 }
 
 // Returns uint by index, reversing the endianness, so that {1000...} bitmask is represented by uint(1)
+// Note, that it returns the underlying buffer word, ignoring the slice offset and length, see Word for the alternative.
 func (bm *BitMask) Uint(index int) uint {
 	return bits.Reverse(bm.store[index])
 }
 
 // Returns uint without reversing, more effective version of Uint method
+// Note, that it returns the underlying buffer word, ignoring the slice offset and length, see WordRaw for the alternative.
 func (bm *BitMask) UintRaw(index int) uint {
 	return bm.store[index]
 }
 
+// Returns the length of bitmask in words (uints), rounded up. Unlike LenUint, doesn't depend on the slice offset.
+func (bm *BitMask) LenWords() int {
+	return int((bm.len + uintSize - 1) / uintSize)
+}
+
+// Returns bits [index*uintSize, (index+1)*uintSize) of bitmask as uint, so that the first of them is the lowest bit of the result.
+// Unlike Uint, it works the same way for sliced and freshly created bitmasks: bits are realigned to start from the beginning of the slice,
+// and bits after Len() are zero.
+func (bm *BitMask) Word(index int) uint {
+	return bits.Reverse(bm.WordRaw(index))
+}
+
+// Same as Word, but without reversing, so that the first bit is the highest bit of the result (as in UintRaw).
+func (bm *BitMask) WordRaw(index int) uint {
+	checkBounds(uint(bm.LenWords()), uint(index))
+	return bm.loadWord(uint(index) * uintSize)
+}
+
+// Go >=1.23 iterator over LenWords() words, returned by Word.
+func (bm *BitMask) Words() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i := uint(0); i < bm.len; i += uintSize {
+			if !yield(bits.Reverse(bm.loadWord(i))) {
+				return
+			}
+		}
+	}
+}
+
 // Sets the bit by bitIndex to 1.
 func (bm *BitMask) Set(bitIndex uint) {
 	checkBounds(bm.len, bitIndex)
@@ -143,6 +173,9 @@ func copyFirstUintSameOffset(totalCopyLen uint, src *BitMask, dst *BitMask) {
 
 func copyLastUintSameOffset(totalCopyLen uint, lastUintIndex uint, src *BitMask, dst *BitMask) {
 	remainderBitsN := (totalCopyLen + src.offset) % uintSize
+	if remainderBitsN == 0 {
+		remainderBitsN = uintSize
+	}
 	copyUintPart(
 		minUint(totalCopyLen, remainderBitsN),
 		src.store[lastUintIndex],
@@ -163,10 +196,8 @@ func Copy(dst *BitMask, src *BitMask) uint {
 	}
 
 	// in what direction to copy (to handle overlapping data)
-	srcDataPtr := (*reflect.SliceHeader)(unsafe.Pointer(&src.store)).Data
-	dstDataPtr := (*reflect.SliceHeader)(unsafe.Pointer(&dst.store)).Data
 	fwdDirection := false
-	if dstDataPtr < srcDataPtr {
+	if dst.bitAddr() < src.bitAddr() {
 		fwdDirection = true
 	}
 
@@ -285,13 +316,12 @@ func Copy(dst *BitMask, src *BitMask) uint {
 // Selects a half-open range which includes the "from" bit, but excludes the "to" one.
 func (bm *BitMask) Slice(fromBit uint, toBit uint) *BitMask {
 	checkSliceBounds(fromBit, toBit, bm.len)
-	if fromBit == toBit {
-		// to avoid empty BitMask with offset
-		return New(0)
-	}
 
 	fromStoreIndex := bm.getStoreIndex(fromBit)
-	toStoreIndex := bm.getStoreIndex(toBit-1) + 1
+	toStoreIndex := fromStoreIndex
+	if fromBit != toBit {
+		toStoreIndex = bm.getStoreIndex(toBit-1) + 1
+	}
 
 	return &BitMask{
 		store:  bm.store[fromStoreIndex:toStoreIndex],
@@ -356,6 +386,69 @@ func (bm *BitMask) Bits() iter.Seq2[uint, bool] {
 	}
 }
 
+// Go >=1.23 iterator through the indexes of set bits only, in ascending order.
+// Works word-at-a-time, so it's much faster than Bits() on sparse bitmasks.
+func (bm *BitMask) SetBits() iter.Seq[uint] {
+	return bm.indexes(0)
+}
+
+// Go >=1.23 iterator through the indexes of cleared bits only, in ascending order.
+// Works word-at-a-time, so it's much faster than Bits() on dense bitmasks.
+func (bm *BitMask) ClearBits() iter.Seq[uint] {
+	return bm.indexes(uintMax)
+}
+
+// Same as SetBits, but in descending order.
+func (bm *BitMask) SetBitsBackward() iter.Seq[uint] {
+	return bm.indexesBackward(0)
+}
+
+// Same as ClearBits, but in descending order.
+func (bm *BitMask) ClearBitsBackward() iter.Seq[uint] {
+	return bm.indexesBackward(uintMax)
+}
+
+// iterates through the indexes of bits which are set after being xor-ed with the flip word
+func (bm *BitMask) indexes(flip uint) iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		if bm.len == 0 {
+			return
+		}
+		for storeIndex := 0; storeIndex < len(bm.store); storeIndex++ {
+			w := (bm.store[storeIndex] ^ flip) & bm.getStoreWordMask(storeIndex)
+			// may overflow for the first word, which is fine, since offset bits are masked
+			wordStartIndex := uint(storeIndex)*uintSize - bm.offset
+			for w != 0 {
+				lz := uint(bits.LeadingZeros(w))
+				if !yield(wordStartIndex + lz) {
+					return
+				}
+				w &^= oneInBE >> lz
+			}
+		}
+	}
+}
+
+// iterates backward through the indexes of bits which are set after being xor-ed with the flip word
+func (bm *BitMask) indexesBackward(flip uint) iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		if bm.len == 0 {
+			return
+		}
+		for storeIndex := len(bm.store) - 1; storeIndex >= 0; storeIndex-- {
+			w := (bm.store[storeIndex] ^ flip) & bm.getStoreWordMask(storeIndex)
+			wordEndIndex := uint(storeIndex)*uintSize + uintSize - 1 - bm.offset
+			for w != 0 {
+				tz := uint(bits.TrailingZeros(w))
+				if !yield(wordEndIndex - tz) {
+					return
+				}
+				w &^= 1 << tz
+			}
+		}
+	}
+}
+
 // Returns string representation of a bitmask in the form "[length]{bits}".
 // For example: [4]{0100}
 // It is O(1) operation and it will skip bits after some amount of them.
@@ -438,6 +531,32 @@ func (bm *BitMask) getBit(bitIndex uint) (*uint, uint) {
 	return &bm.store[storeIndex], mask
 }
 
+// returns up to uintSize bits starting from bitIndex, aligned to the most significant bit (store endianness),
+// bits after the end of bitmask are zeroed
+func (bm *BitMask) loadWord(bitIndex uint) uint {
+	storeIndex := bm.getStoreIndex(bitIndex)
+	bitOffset := bm.getBitOffset(bitIndex)
+	n := minUint(uintSize, bm.len-bitIndex)
+
+	w := bm.store[storeIndex] << bitOffset
+	if bitOffset+n > uintSize {
+		w |= bm.store[storeIndex+1] >> (uintSize - bitOffset)
+	}
+	return w & (uintMax << (uintSize - n))
+}
+
+// writes n most significant bits of w starting from bitIndex, other bits are left untouched
+func (bm *BitMask) storeWord(bitIndex uint, n uint, w uint) {
+	storeIndex := bm.getStoreIndex(bitIndex)
+	bitOffset := bm.getBitOffset(bitIndex)
+
+	firstLen := minUint(n, uintSize-bitOffset)
+	copyUintPart(firstLen, w, 0, &bm.store[storeIndex], bitOffset)
+	if n > firstLen {
+		copyUintPart(n-firstLen, w, firstLen, &bm.store[storeIndex+1], 0)
+	}
+}
+
 func (bm *BitMask) getStoreWordMask(storeIndex int) uint {
 	mask := uintMax
 	if storeIndex == 0 {
@@ -450,6 +569,29 @@ func (bm *BitMask) getStoreWordMask(storeIndex int) uint {
 	return mask
 }
 
+// returns a copy of bitmask which doesn't share the buffer with the original one
+func (bm *BitMask) clone() *BitMask {
+	c := New(bm.len)
+	Copy(c, bm)
+	return c
+}
+
+// returns the position of the first bit of bitmask in memory (in bits), used to detect overlapping bitmasks
+func (bm *BitMask) bitAddr() uint64 {
+	if len(bm.store) == 0 {
+		return 0
+	}
+	return uint64(uintptr(unsafe.Pointer(&bm.store[0])))*8 + uint64(bm.offset)
+}
+
+func overlaps(a *BitMask, b *BitMask) bool {
+	if a.len == 0 || b.len == 0 {
+		return false
+	}
+	aAddr, bAddr := a.bitAddr(), b.bitAddr()
+	return aAddr < bAddr+uint64(b.len) && bAddr < aAddr+uint64(a.len)
+}
+
 func minUint(a uint, b uint) uint {
 	if a < b {
 		return a
@@ -161,6 +161,18 @@ func TestCopy(t *testing.T) {
 			dstSlice:     slice{uintSize, 2 * uintSize},
 			expectedBase: NewFromUint(uintMax, uintMax).String(),
 		},
+		"2w_same_offset0_whole_words": {
+			base:         NewFromUint(uintMax, 1, 0, 0),
+			srcSlice:     slice{0, 2 * uintSize},
+			dstSlice:     slice{2 * uintSize, 4 * uintSize},
+			expectedBase: NewFromUint(uintMax, 1, uintMax, 1).String(),
+		},
+		"3w_overlap3_bw": {
+			base:         NewFromUint(0b1000, uintMax, 1),
+			srcSlice:     slice{3, 3 * uintSize},
+			dstSlice:     slice{0, 3*uintSize - 3},
+			expectedBase: NewFromUint(uintMax>>(uintSize-3)<<(uintSize-3)|1, uintMax>>3|1<<(uintSize-3), 0).String(),
+		},
 		"small_src": {
 			base:         NewFromUint(1, 0),
 			srcSlice:     slice{0, 1},
@@ -175,42 +187,42 @@ func TestCopy(t *testing.T) {
 		},
 
 		"1w_overlap1_fw": {
-			base:         NewFromUint(bits.Reverse(0b1000000000000000000000000000000000000000000000000000000000000010)),
+			base:         NewFromUint(1 | 1<<(uintSize-2)),
 			srcSlice:     slice{0, uintSize - 1},
 			dstSlice:     slice{1, uintSize},
-			expectedBase: NewFromUint(bits.Reverse(0b1100000000000000000000000000000000000000000000000000000000000001)).String(),
+			expectedBase: NewFromUint(0b11 | 1<<(uintSize-1)).String(),
 		},
 		"1w_overlap2_fw": {
-			base:         NewFromUint(bits.Reverse(0b1000000000000000000000000000000000000000000000000000000000000100)),
+			base:         NewFromUint(1 | 1<<(uintSize-3)),
 			srcSlice:     slice{0, uintSize - 2},
 			dstSlice:     slice{2, uintSize},
-			expectedBase: NewFromUint(bits.Reverse(0b1010000000000000000000000000000000000000000000000000000000000001)).String(),
+			expectedBase: NewFromUint(0b101 | 1<<(uintSize-1)).String(),
 		},
 
 		"1w_overlap1_fw_inverse": {
-			base:         NewFromUint(bits.Reverse(0b0111111111111111111111111111111111111111111111111111111111111101)),
+			base:         NewFromUint(uintMax &^ (1 | 1<<(uintSize-2))),
 			srcSlice:     slice{0, uintSize - 1},
 			dstSlice:     slice{1, uintSize},
-			expectedBase: NewFromUint(bits.Reverse(0b0011111111111111111111111111111111111111111111111111111111111110)).String(),
+			expectedBase: NewFromUint(uintMax &^ (0b11 | 1<<(uintSize-1))).String(),
 		},
 		"1w_overlap2_fw_inverse": {
-			base:         NewFromUint(bits.Reverse(0b0111111111111111111111111111111111111111111111111111111111111011)),
+			base:         NewFromUint(uintMax &^ (1 | 1<<(uintSize-3))),
 			srcSlice:     slice{0, uintSize - 2},
 			dstSlice:     slice{2, uintSize},
-			expectedBase: NewFromUint(bits.Reverse(0b0101111111111111111111111111111111111111111111111111111111111110)).String(),
+			expectedBase: NewFromUint(uintMax &^ (0b101 | 1<<(uintSize-1))).String(),
 		},
 
 		"1w_overlap1_bw": {
-			base:         NewFromUint(bits.Reverse(0b0100000000000000000000000000000000000000000000000000000000000001)),
+			base:         NewFromUint(0b10 | 1<<(uintSize-1)),
 			srcSlice:     slice{1, uintSize},
 			dstSlice:     slice{0, uintSize - 1},
-			expectedBase: NewFromUint(bits.Reverse(0b1000000000000000000000000000000000000000000000000000000000000011)).String(),
+			expectedBase: NewFromUint(1 | 0b11<<(uintSize-2)).String(),
 		},
 		"1w_overlap2_bw": {
-			base:         NewFromUint(bits.Reverse(0b0010000000000000000000000000000000000000000000000000000000000001)),
+			base:         NewFromUint(0b100 | 1<<(uintSize-1)),
 			srcSlice:     slice{2, uintSize},
 			dstSlice:     slice{0, uintSize - 2},
-			expectedBase: NewFromUint(bits.Reverse(0b1000000000000000000000000000000000000000000000000000000000000101)).String(),
+			expectedBase: NewFromUint(1 | 0b101<<(uintSize-3)).String(),
 		},
 
 		"2w_overlap1l_fw": {
@@ -249,7 +261,7 @@ func TestCopy(t *testing.T) {
 			base:         NewFromUint(uintMax, 0, uintMax),
 			srcSlice:     slice{uintSize, 2*uintSize + 2},
 			dstSlice:     slice{0, uintSize + 2},
-			expectedBase: NewFromUint(0, bits.Reverse(0b1100000000000000000000000000000000000000000000000000000000000000), uintMax).String(),
+			expectedBase: NewFromUint(0, 0b11, uintMax).String(),
 		},
 
 		"3w_overlap_same_offset1_fw": {
@@ -262,33 +274,33 @@ func TestCopy(t *testing.T) {
 			base:         NewFromUint(uintMax, 0, uintMax),
 			srcSlice:     slice{uintSize + 1, 2*uintSize + 2},
 			dstSlice:     slice{1, uintSize + 2},
-			expectedBase: NewFromUint(1, bits.Reverse(0b1100000000000000000000000000000000000000000000000000000000000000), uintMax).String(),
+			expectedBase: NewFromUint(1, 0b11, uintMax).String(),
 		},
 
 		"2w_small_dst_same_offset0": {
 			base:         NewFromUint(uintMax, 0),
 			srcSlice:     slice{0, uintSize},
 			dstSlice:     slice{uintSize, uintSize + 2},
-			expectedBase: NewFromUint(uintMax, bits.Reverse(0b1100000000000000000000000000000000000000000000000000000000000000)).String(),
+			expectedBase: NewFromUint(uintMax, 0b11).String(),
 		},
 		"3w_small_dst_same_offset0": {
 			base:         NewFromUint(uintMax, 0, 0),
 			srcSlice:     slice{0, uintSize},
 			dstSlice:     slice{uintSize * 2, uintSize*2 + 2},
-			expectedBase: NewFromUint(uintMax, 0, bits.Reverse(0b1100000000000000000000000000000000000000000000000000000000000000)).String(),
+			expectedBase: NewFromUint(uintMax, 0, 0b11).String(),
 		},
 
 		"2w_small_dst_same_offset2": {
 			base:         NewFromUint(uintMax, 0),
 			srcSlice:     slice{2, uintSize},
 			dstSlice:     slice{uintSize + 2, uintSize + 4},
-			expectedBase: NewFromUint(uintMax, bits.Reverse(0b0011000000000000000000000000000000000000000000000000000000000000)).String(),
+			expectedBase: NewFromUint(uintMax, 0b1100).String(),
 		},
 		"3w_small_dst_same_offset2": {
 			base:         NewFromUint(uintMax, 0, 0),
 			srcSlice:     slice{2, uintSize},
 			dstSlice:     slice{uintSize*2 + 2, uintSize*2 + 4},
-			expectedBase: NewFromUint(uintMax, 0, bits.Reverse(0b0011000000000000000000000000000000000000000000000000000000000000)).String(),
+			expectedBase: NewFromUint(uintMax, 0, 0b1100).String(),
 		},
 	}
 	for name, tc := range tests {
@@ -511,8 +523,8 @@ func TestSliceWithString(t *testing.T) {
 		expected string
 	}{
 		"0w_empty": {New(0), 0, 0, "[0]{}"},
-		"1w_full":  {NewFromUint(0), 0, 64, "[64]{0000000000000000000000000000000000000000000000000000000000000000}"},
-		"1w1_full": {NewFromUint(1), 0, 64, "[64]{1000000000000000000000000000000000000000000000000000000000000000}"},
+		"1w_full":  {NewFromUint(0), 0, uintSize, "[" + fmt.Sprint(uintSize) + "]{" + zeros(uintSize) + "}"},
+		"1w1_full": {NewFromUint(1), 0, uintSize, "[" + fmt.Sprint(uintSize) + "]{1" + zeros(uintSize-1) + "}"},
 		"1w_left": {
 			NewFromUint(0b0000000000000000000000000000000000000000000000000000001000000101),
 			0,
@@ -520,15 +532,15 @@ func TestSliceWithString(t *testing.T) {
 			"[10]{1010000001}",
 		},
 		"1w_right": {
-			NewFromUint(0b1110000000000000000000000000000000000000000000000000000000001001),
+			NewFromUint(0b111<<(uintSize-3) | 0b1001),
 			uintSize - 4,
 			uintSize,
 			"[4]{0111}",
 		},
 		"1w_middle": {
-			NewFromUint(0b0000000000000000000010010110000000000000000000000000000000000000),
-			37,
-			44,
+			NewFromUint(0b1001011 << (uintSize/2 + 5)),
+			uintSize/2 + 5,
+			uintSize/2 + 12,
 			"[7]{1101001}",
 		},
 
@@ -539,15 +551,15 @@ func TestSliceWithString(t *testing.T) {
 			"[10]{1011111111}",
 		},
 		"2w_lright": {
-			NewFromUint(0b1110000000000000000000000000000000000000000000000000000000001001, uintMax),
+			NewFromUint(0b111<<(uintSize-3)|0b1001, uintMax),
 			uintSize - 4,
 			uintSize,
 			"[4]{0111}",
 		},
 		"2w_lmiddle": {
-			NewFromUint(0b0000000000000000000010010110000000000000000000000000000000000000, uintMax),
-			37,
-			44,
+			NewFromUint(0b1001011<<(uintSize/2+5), uintMax),
+			uintSize/2 + 5,
+			uintSize/2 + 12,
 			"[7]{1101001}",
 		},
 
@@ -558,15 +570,15 @@ func TestSliceWithString(t *testing.T) {
 			"[10]{1111111101}",
 		},
 		"2w_rright": {
-			NewFromUint(uintMax, 0b1110000000000000000000000000000000000000000000000000000000001001),
+			NewFromUint(uintMax, 0b111<<(uintSize-3)|0b1001),
 			uintSize + uintSize - 4,
 			uintSize + uintSize,
 			"[4]{0111}",
 		},
 		"2w_rmiddle": {
-			NewFromUint(uintMax, 0b0000000000000000000010010110000000000000000000000000000000000000),
-			uintSize + 37,
-			uintSize + 44,
+			NewFromUint(uintMax, 0b1001011<<(uintSize/2+5)),
+			uintSize + uintSize/2 + 5,
+			uintSize + uintSize/2 + 12,
 			"[7]{1101001}",
 		},
 
@@ -581,13 +593,13 @@ func TestSliceWithString(t *testing.T) {
 			NewFromUint(uintMax, 0, uintMax),
 			uintSize,
 			uintSize * 2,
-			"[64]{0000000000000000000000000000000000000000000000000000000000000000}",
+			"[" + fmt.Sprint(uintSize) + "]{" + zeros(uintSize) + "}",
 		},
 		"3w_middle_outer": {
 			NewFromUint(uintMax, 0, uintMax),
 			uintSize - 1,
 			uintSize*2 + 1,
-			"[66]{1 0000000000000000000000000000000000000000000000000000000000000000 1}",
+			"[" + fmt.Sprint(uintSize+2) + "]{1 " + zeros(uintSize) + " 1}",
 		},
 	}
 
@@ -623,71 +635,71 @@ func TestRangeOps(t *testing.T) {
 		"1w_cleared_clear": {
 			NewFromUint(0),
 			[]rangeOps{ClearAll},
-			"[64]{0000000000000000000000000000000000000000000000000000000000000000}",
+			"[" + fmt.Sprint(uintSize) + "]{" + zeros(uintSize) + "}",
 		},
 		"1w_set_clear": {
 			NewFromUint(uintMax),
 			[]rangeOps{ClearAll},
-			"[64]{0000000000000000000000000000000000000000000000000000000000000000}",
+			"[" + fmt.Sprint(uintSize) + "]{" + zeros(uintSize) + "}",
 		},
 
 		"1w_cleared_set": {
 			NewFromUint(0),
 			[]rangeOps{SetAll},
-			"[64]{1111111111111111111111111111111111111111111111111111111111111111}",
+			"[" + fmt.Sprint(uintSize) + "]{" + ones(uintSize) + "}",
 		},
 		"1w_set_set": {
 			NewFromUint(uintMax),
 			[]rangeOps{SetAll},
-			"[64]{1111111111111111111111111111111111111111111111111111111111111111}",
+			"[" + fmt.Sprint(uintSize) + "]{" + ones(uintSize) + "}",
 		},
 
 		"1w_cleared_toggle": {
 			NewFromUint(0),
 			[]rangeOps{ToggleAll},
-			"[64]{1111111111111111111111111111111111111111111111111111111111111111}",
+			"[" + fmt.Sprint(uintSize) + "]{" + ones(uintSize) + "}",
 		},
 		"1w_set_toggle": {
 			NewFromUint(uintMax),
 			[]rangeOps{ToggleAll},
-			"[64]{0000000000000000000000000000000000000000000000000000000000000000}",
+			"[" + fmt.Sprint(uintSize) + "]{" + zeros(uintSize) + "}",
 		},
 
 		"65b_mixed_clear": {
-			NewFromUint(0, 1).Slice(0, 65),
+			NewFromUint(0, 1).Slice(0, uintSize+1),
 			[]rangeOps{ClearAll},
-			"[65]{0000000000000000000000000000000000000000000000000000000000000000 0}",
+			"[" + fmt.Sprint(uintSize+1) + "]{" + zeros(uintSize) + " 0}",
 		},
 		"65b_mixed_set": {
-			NewFromUint(uintMax, 0).Slice(0, 65),
+			NewFromUint(uintMax, 0).Slice(0, uintSize+1),
 			[]rangeOps{SetAll},
-			"[65]{1111111111111111111111111111111111111111111111111111111111111111 1}",
+			"[" + fmt.Sprint(uintSize+1) + "]{" + ones(uintSize) + " 1}",
 		},
 		"65b_mixed_toggle": {
-			NewFromUint(uintMax, 0).Slice(0, 65),
+			NewFromUint(uintMax, 0).Slice(0, uintSize+1),
 			[]rangeOps{ToggleAll},
-			"[65]{0000000000000000000000000000000000000000000000000000000000000000 1}",
+			"[" + fmt.Sprint(uintSize+1) + "]{" + zeros(uintSize) + " 1}",
 		},
 		"65b_mixed_and_sliced_toggle": {
-			NewFromUint(uintMax, 0).Slice(5, 65),
+			NewFromUint(uintMax, 0).Slice(5, uintSize+1),
 			[]rangeOps{ToggleAll},
-			"[60]{00000000000000000000000000000000000000000000000000000000000 1}",
+			"[" + fmt.Sprint(uintSize-4) + "]{" + zeros(uintSize-5) + " 1}",
 		},
 
 		"3w_mixed_and_2xsliced_toggle": {
 			NewFromUint(uintMax, 0, uintMax).Slice(1, 3*uintSize-1).Slice(uintSize-2, 2*uintSize),
 			[]rangeOps{ToggleAll},
-			"[66]{0 1111111111111111111111111111111111111111111111111111111111111111 0}",
+			"[" + fmt.Sprint(uintSize+2) + "]{0 " + ones(uintSize) + " 0}",
 		},
 		"3w_mixed_and_2xsliced_2xtoggle": {
 			NewFromUint(uintMax, 0, uintMax).Slice(1, 3*uintSize-1).Slice(uintSize-2, 2*uintSize),
 			[]rangeOps{ToggleAll, ToggleAll},
-			"[66]{1 0000000000000000000000000000000000000000000000000000000000000000 1}",
+			"[" + fmt.Sprint(uintSize+2) + "]{1 " + zeros(uintSize) + " 1}",
 		},
 		"3w_mixed_and_2xsliced_3xtoggle": {
 			NewFromUint(uintMax, 0, uintMax).Slice(1, 3*uintSize-1).Slice(uintSize-2, 2*uintSize),
 			[]rangeOps{ToggleAll, ToggleAll, ToggleAll},
-			"[66]{0 1111111111111111111111111111111111111111111111111111111111111111 0}",
+			"[" + fmt.Sprint(uintSize+2) + "]{0 " + ones(uintSize) + " 0}",
 		},
 	}
 
@@ -714,6 +726,10 @@ func zeros(n uint) string {
 	return fmt.Sprintf("%0"+fmt.Sprint(n)+"b", 0)
 }
 
+func ones(n uint) string {
+	return strings.Repeat("1", int(n))
+}
+
 func zerosWords(n uint) string {
 	var b strings.Builder
 	for i := uint(0); i < n; i++ {
@@ -725,18 +741,26 @@ func zerosWords(n uint) string {
 	return b.String()
 }
 
+func lenPrefix(n uint) string {
+	return fmt.Sprintf("[%v]{", n)
+}
+
+func skipped(words uint) string {
+	return fmt.Sprintf(" <more %v bits> ", words*uintSize)
+}
+
 func TestStringSkips(t *testing.T) {
 	tests := map[string]struct {
 		source   *BitMask
 		expected string
 	}{
-		"512_not_skipped": {New(512), "[512]{" + zerosWords(8) + "}"},
-		"513":             {New(513), "[513]{" + zerosWords(4) + " <more 64 bits> " + zerosWords(3) + " 0}"},
-		"576":             {New(576), "[576]{" + zerosWords(4) + " <more 64 bits> " + zerosWords(4) + "}"},
-		"640":             {New(640), "[640]{" + zerosWords(4) + " <more 128 bits> " + zerosWords(4) + "}"},
-		"641":             {New(641), "[641]{" + zerosWords(4) + " <more 192 bits> " + zerosWords(3) + " 0}"},
-		"639":             {New(639), "[639]{" + zerosWords(4) + " <more 128 bits> " + zerosWords(3) + " " + zeros(63) + "}"},
-		"960":             {New(960), "[960]{" + zerosWords(4) + " <more 448 bits> " + zerosWords(4) + "}"},
+		"8w_not_skipped": {New(8 * uintSize), lenPrefix(8*uintSize) + zerosWords(8) + "}"},
+		"8w+1":           {New(8*uintSize + 1), lenPrefix(8*uintSize+1) + zerosWords(4) + skipped(1) + zerosWords(3) + " 0}"},
+		"9w":             {New(9 * uintSize), lenPrefix(9*uintSize) + zerosWords(4) + skipped(1) + zerosWords(4) + "}"},
+		"10w":            {New(10 * uintSize), lenPrefix(10*uintSize) + zerosWords(4) + skipped(2) + zerosWords(4) + "}"},
+		"10w+1":          {New(10*uintSize + 1), lenPrefix(10*uintSize+1) + zerosWords(4) + skipped(3) + zerosWords(3) + " 0}"},
+		"10w-1":          {New(10*uintSize - 1), lenPrefix(10*uintSize-1) + zerosWords(4) + skipped(2) + zerosWords(3) + " " + zeros(uintSize-1) + "}"},
+		"15w":            {New(15 * uintSize), lenPrefix(15*uintSize) + zerosWords(4) + skipped(7) + zerosWords(4) + "}"},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -751,7 +775,7 @@ func TestNewFromUint(t *testing.T) {
 	bm.Toggle(2)
 	bm.Toggle(3)
 	bm.Clear(3)
-	assert.Equal(t, "[64]{1110000000000000000000000000000000000000000000000000000000000000}", bm.String())
+	assert.Equal(t, "["+fmt.Sprint(uintSize)+"]{111"+zeros(uintSize-3)+"}", bm.String())
 }
 
 func TestDocExample(t *testing.T) {
@@ -851,8 +875,8 @@ func TestUintRaw(t *testing.T) {
 
 	assert.Equal(t, []int{uintSize - 1, uintSize + uintSize - 1}, indexes)
 
-	assert.Equal(t, uint(1<<63), bm.Uint(0))
-	assert.Equal(t, uint(1<<63), bm.Uint(1))
+	assert.Equal(t, uint(1)<<(uintSize-1), bm.Uint(0))
+	assert.Equal(t, uint(1)<<(uintSize-1), bm.Uint(1))
 	assert.Equal(t, uint(0), bm.Uint(2))
 
 	assert.Equal(t, uint(1), bm.UintRaw(0))
@@ -869,8 +893,8 @@ func TestUintRawNocopy(t *testing.T) {
 
 	assert.Equal(t, []uint{uintSize - 1, uintSize + uintSize - 1}, indexes)
 
-	assert.Equal(t, uint(1<<63), bm.Uint(0))
-	assert.Equal(t, uint(1<<63), bm.Uint(1))
+	assert.Equal(t, uint(1)<<(uintSize-1), bm.Uint(0))
+	assert.Equal(t, uint(1)<<(uintSize-1), bm.Uint(1))
 	assert.Equal(t, uint(0), bm.Uint(2))
 
 	assert.Equal(t, uint(1), bm.UintRaw(0))
@@ -882,10 +906,101 @@ func TestUintRawNocopy(t *testing.T) {
 	assert.Equal(t, 3, bm.LenUint())
 
 	assert.Equal(t, uint(0), bm.Uint(0))
-	assert.Equal(t, uint(1<<63), bm.Uint(1))
-	assert.Equal(t, uint(1<<63), bm.Uint(2))
+	assert.Equal(t, uint(1)<<(uintSize-1), bm.Uint(1))
+	assert.Equal(t, uint(1)<<(uintSize-1), bm.Uint(2))
 
 	assert.Equal(t, uint(0), bm.UintRaw(0))
 	assert.Equal(t, uint(1), bm.UintRaw(1))
 	assert.Equal(t, uint(1), bm.UintRaw(2))
 }
+
+func TestSetClearBits(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 200 {
+		bm, _ := randomSlice(rnd, 2*uintSize, uint(rnd.Intn(5*uintSize)))
+
+		expectedSet := []uint{}
+		expectedClear := []uint{}
+		for idx, isSet := range bm.Bits() {
+			if isSet {
+				expectedSet = append(expectedSet, idx)
+			} else {
+				expectedClear = append(expectedClear, idx)
+			}
+		}
+
+		assert.Equal(t, expectedSet, append([]uint{}, slices.Collect(bm.SetBits())...))
+		assert.Equal(t, expectedClear, append([]uint{}, slices.Collect(bm.ClearBits())...))
+
+		slices.Reverse(expectedSet)
+		slices.Reverse(expectedClear)
+		assert.Equal(t, expectedSet, append([]uint{}, slices.Collect(bm.SetBitsBackward())...))
+		assert.Equal(t, expectedClear, append([]uint{}, slices.Collect(bm.ClearBitsBackward())...))
+	}
+}
+
+func TestSetBitsBreak(t *testing.T) {
+	bm := New(300)
+	bm.Set(1)
+	bm.Set(10)
+	bm.Set(100)
+	bm.Set(200)
+
+	collected := []uint{}
+	for idx := range bm.SetBits() {
+		collected = append(collected, idx)
+		if idx == 100 {
+			break
+		}
+	}
+	assert.Equal(t, []uint{1, 10, 100}, collected)
+
+	collected = collected[:0]
+	for idx := range bm.SetBitsBackward() {
+		collected = append(collected, idx)
+		if idx == 10 {
+			break
+		}
+	}
+	assert.Equal(t, []uint{200, 100, 10}, collected)
+}
+
+func TestWord(t *testing.T) {
+	bm := NewFromUint(0b1011<<(uintSize-4), 0b110, uintMax)
+	sliced := bm.Slice(uintSize-3, 2*uintSize+7)
+
+	assert.Equal(t, 2, sliced.LenWords())
+	assert.Equal(t, 3, sliced.LenUint())
+	assert.Equal(t, uint(0b1011)<<(uintSize-4)>>(uintSize-3)|0b110<<3, sliced.Word(0))
+	assert.Equal(t, bits.Reverse(sliced.Word(0)), sliced.WordRaw(0))
+	// bits after the end of slice are zero
+	assert.Equal(t, uint(0b1111111000), sliced.Word(1))
+	assert.Panics(t, func() { sliced.Word(2) })
+
+	assert.Equal(t, []uint{sliced.Word(0), sliced.Word(1)}, slices.Collect(sliced.Words()))
+
+	// same as Uint for the bitmask without offset
+	for i := 0; i < bm.LenWords(); i++ {
+		assert.Equal(t, bm.Uint(i), bm.Word(i))
+		assert.Equal(t, bm.UintRaw(i), bm.WordRaw(i))
+	}
+	assert.Equal(t, 0, New(0).LenWords())
+	assert.Empty(t, slices.Collect(New(0).Words()))
+}
+
+func TestWordsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 200 {
+		bm, _ := randomSlice(rnd, 2*uintSize, uint(rnd.Intn(3*uintSize)))
+
+		i := 0
+		for w := range bm.Words() {
+			for j := uint(0); j < uintSize; j++ {
+				bitIndex := uint(i)*uintSize + j
+				assert.Equal(t, bitIndex < bm.Len() && bm.IsSet(bitIndex), w&(1<<j) != 0)
+			}
+			i++
+		}
+		assert.Equal(t, bm.LenWords(), i)
+	}
+}
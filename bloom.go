@@ -0,0 +1,192 @@
+package bitmask
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const bloomFormatVersion = 1
+
+// upper bound of the number of hash functions, giving the false positive rate of about 2^-64
+const bloomMaxK = 64
+
+const fnvOffset64 = 14695981039346656037
+const fnvPrime64 = 1099511628211
+
+// Returned when combining Bloom filters of different size, number of hash functions or seed.
+var ErrIncompatibleBloom = errors.New("bitmask: incompatible bloom filters")
+
+// Bloom filter, a probabilistic set, which can tell that an item is definitely not in the set, or that it probably is.
+//
+// Items are hashed with 64-bit FNV-1a, seeded by the seed, and k bit indexes are derived from the hash using double hashing.
+// Hashing doesn't depend on the process or platform, so filters, which were persisted (see MarshalBinary) or created with the same
+// parameters in another process, can be tested and combined.
+type Bloom struct {
+	bits *BitMask
+	k    uint
+	seed uint64
+}
+
+// Creates new empty Bloom filter, sized for n items with the false positive rate p (0 < p < 1).
+// Filters created with the same arguments are compatible (see Union and Intersect).
+func NewBloom(n uint, p float64, seed uint64) *Bloom {
+	if !(p > 0 && p < 1) {
+		panic(fmt.Sprintf("false positive rate %v is out of range (0, 1)", p))
+	}
+	n = max(n, 1)
+	m := uint(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint(math.Round(float64(m) / float64(n) * math.Ln2))
+	return &Bloom{bits: New(m), k: min(max(k, 1), bloomMaxK), seed: seed}
+}
+
+// Returns the number of bits in the filter.
+func (f *Bloom) Len() uint {
+	return f.bits.len
+}
+
+// Returns the number of hash functions (bits set per item).
+func (f *Bloom) K() uint {
+	return f.k
+}
+
+// Returns the seed of the hash function.
+func (f *Bloom) Seed() uint64 {
+	return f.seed
+}
+
+// Returns underlying bitmask, modifying it will affect the filter.
+func (f *Bloom) BitMask() *BitMask {
+	return f.bits
+}
+
+// Adds an item to the filter.
+func (f *Bloom) Add(item []byte) {
+	f.add(bloomHash(f.seed, item))
+}
+
+// Adds a string item to the filter, same as Add([]byte(item)), but without allocation.
+func (f *Bloom) AddString(item string) {
+	f.add(bloomHash(f.seed, item))
+}
+
+// Returns false if the item was definitely not added to the filter, and true if it probably was.
+func (f *Bloom) Test(item []byte) bool {
+	return f.test(bloomHash(f.seed, item))
+}
+
+// Same as Test([]byte(item)), but without allocation.
+func (f *Bloom) TestString(item string) bool {
+	return f.test(bloomHash(f.seed, item))
+}
+
+// Merges items of other filter into this one. Returns ErrIncompatibleBloom, if filters were created with different parameters.
+func (f *Bloom) Union(other *Bloom) error {
+	if err := f.checkCompatible(other); err != nil {
+		return err
+	}
+	f.bits.Or(other.bits)
+	return nil
+}
+
+// Keeps only items, which are (probably) in both filters. Returns ErrIncompatibleBloom, if filters were created with different parameters.
+// Resulting filter may have a higher false positive rate than the one built from the intersection of items.
+func (f *Bloom) Intersect(other *Bloom) error {
+	if err := f.checkCompatible(other); err != nil {
+		return err
+	}
+	f.bits.And(other.bits)
+	return nil
+}
+
+// Returns estimated number of distinct items, added to the filter, based on the number of set bits.
+// Returns +Inf, if all bits are set.
+func (f *Bloom) EstimatedCount() float64 {
+	m, x := float64(f.bits.len), float64(f.bits.Count())
+	return -m / float64(f.k) * math.Log1p(-x/m)
+}
+
+// Implements encoding.BinaryMarshaler.
+//
+// Format: version byte (1), uvarint number of hash functions, 8 bytes of seed (little-endian),
+// followed by the bitmask in its binary format (see BitMask.AppendBinary).
+func (f *Bloom) MarshalBinary() ([]byte, error) {
+	b := []byte{bloomFormatVersion}
+	b = binary.AppendUvarint(b, uint64(f.k))
+	b = binary.LittleEndian.AppendUint64(b, f.seed)
+	return f.bits.AppendBinary(b)
+}
+
+// Implements encoding.BinaryUnmarshaler.
+func (f *Bloom) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty data", ErrInvalidEncoding)
+	}
+	if data[0] != bloomFormatVersion {
+		return fmt.Errorf("%w: unsupported bloom filter version %v", ErrInvalidEncoding, data[0])
+	}
+	k, n := binary.Uvarint(data[1:])
+	if n <= 0 || k == 0 || k > bloomMaxK {
+		return fmt.Errorf("%w: invalid number of hash functions", ErrInvalidEncoding)
+	}
+	data = data[1+n:]
+	if len(data) < 8 {
+		return fmt.Errorf("%w: missing seed", ErrInvalidEncoding)
+	}
+	seed := binary.LittleEndian.Uint64(data)
+	bits := &BitMask{}
+	if err := bits.UnmarshalBinary(data[8:]); err != nil {
+		return err
+	}
+	if bits.len == 0 {
+		return fmt.Errorf("%w: empty bloom filter", ErrInvalidEncoding)
+	}
+	*f = Bloom{bits: bits, k: uint(k), seed: seed}
+	return nil
+}
+
+func (f *Bloom) add(h uint64) {
+	for i := range f.k {
+		f.bits.Set(f.index(h, i))
+	}
+}
+
+func (f *Bloom) test(h uint64) bool {
+	for i := range f.k {
+		if !f.bits.IsSet(f.index(h, i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// returns bit index of i-th hash function
+func (f *Bloom) index(h uint64, i uint) uint {
+	// second hash is derived from the first one by the splitmix64 finalizer, and made odd to never be zero
+	h2 := h
+	h2 = (h2 ^ (h2 >> 30)) * 0xbf58476d1ce4e5b9
+	h2 = (h2 ^ (h2 >> 27)) * 0x94d049bb133111eb
+	h2 = (h2 ^ (h2 >> 31)) | 1
+	return uint((h + uint64(i)*h2) % uint64(f.bits.len))
+}
+
+func (f *Bloom) checkCompatible(other *Bloom) error {
+	if f.bits.len != other.bits.len || f.k != other.k || f.seed != other.seed {
+		return fmt.Errorf("%w: (len %v, k %v, seed %v) and (len %v, k %v, seed %v)",
+			ErrIncompatibleBloom, f.bits.len, f.k, f.seed, other.bits.len, other.k, other.seed)
+	}
+	return nil
+}
+
+// 64-bit FNV-1a of the seed (8 bytes, little-endian) followed by the data
+func bloomHash[T string | []byte](seed uint64, data T) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < 8; i++ {
+		h = (h ^ (seed >> (8 * i) & 0xff)) * fnvPrime64
+	}
+	for i := 0; i < len(data); i++ {
+		h = (h ^ uint64(data[i])) * fnvPrime64
+	}
+	return h
+}
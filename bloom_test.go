@@ -0,0 +1,146 @@
+package bitmask
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloom(t *testing.T) {
+	f := NewBloom(1000, 0.01, 42)
+	// optimal parameters for n=1000, p=0.01
+	assert.Equal(t, uint(9586), f.Len())
+	assert.Equal(t, uint(7), f.K())
+	assert.Equal(t, uint64(42), f.Seed())
+
+	for i := range 1000 {
+		if i%2 == 0 {
+			f.Add([]byte(fmt.Sprint("item", i)))
+		} else {
+			f.AddString(fmt.Sprint("item", i))
+		}
+	}
+
+	for i := range 1000 {
+		assert.True(t, f.Test([]byte(fmt.Sprint("item", i))))
+		assert.True(t, f.TestString(fmt.Sprint("item", i)))
+	}
+
+	falsePositives := 0
+	for i := range 10000 {
+		if f.TestString(fmt.Sprint("other", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 200)
+
+	assert.InDelta(t, 1000, f.EstimatedCount(), 50)
+	assert.Equal(t, 0.0, NewBloom(1000, 0.01, 42).EstimatedCount())
+}
+
+func TestBloomHash(t *testing.T) {
+	// same as FNV-1a of the seed bytes followed by the data
+	h := fnv.New64a()
+	h.Write(binary.LittleEndian.AppendUint64(nil, 0x0102030405060708))
+	h.Write([]byte("hello"))
+	assert.Equal(t, h.Sum64(), bloomHash(0x0102030405060708, "hello"))
+	assert.Equal(t, h.Sum64(), bloomHash(0x0102030405060708, []byte("hello")))
+
+	assert.NotEqual(t, bloomHash(1, "hello"), bloomHash(2, "hello"))
+}
+
+func TestBloomSeed(t *testing.T) {
+	a, b := NewBloom(100, 0.01, 1), NewBloom(100, 0.01, 2)
+	a.AddString("item")
+	b.AddString("item")
+	assert.NotEqual(t, bitString(a.BitMask()), bitString(b.BitMask()))
+}
+
+func TestBloomUnionIntersect(t *testing.T) {
+	a, b := NewBloom(100, 0.01, 7), NewBloom(100, 0.01, 7)
+	a.AddString("a")
+	a.AddString("both")
+	b.AddString("b")
+	b.AddString("both")
+
+	union := NewBloom(100, 0.01, 7)
+	assert.NoError(t, union.Union(a))
+	assert.NoError(t, union.Union(b))
+	assert.True(t, union.TestString("a"))
+	assert.True(t, union.TestString("b"))
+	assert.True(t, union.TestString("both"))
+
+	assert.NoError(t, a.Intersect(b))
+	assert.False(t, a.TestString("a"))
+	assert.False(t, a.TestString("b"))
+	assert.True(t, a.TestString("both"))
+
+	incompatible := map[string]*Bloom{
+		"len":  NewBloom(200, 0.01, 7),
+		"k":    {bits: New(b.Len()), k: b.K() + 1, seed: 7},
+		"seed": NewBloom(100, 0.01, 8),
+	}
+	for name, other := range incompatible {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, errors.Is(b.Union(other), ErrIncompatibleBloom))
+			assert.True(t, errors.Is(b.Intersect(other), ErrIncompatibleBloom))
+		})
+	}
+}
+
+func TestBloomEstimatedCountFull(t *testing.T) {
+	f := NewBloom(10, 0.1, 0)
+	f.BitMask().SetAll()
+	assert.True(t, math.IsInf(f.EstimatedCount(), 1))
+}
+
+func TestBloomBinary(t *testing.T) {
+	f := NewBloom(100, 0.05, 0xdeadbeef)
+	f.AddString("x")
+	f.AddString("y")
+
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+	bitsData, _ := f.BitMask().MarshalBinary()
+	assert.Equal(t, []byte{1, byte(f.K()), 0xef, 0xbe, 0xad, 0xde, 0, 0, 0, 0}, data[:10])
+	assert.Equal(t, bitsData, data[10:])
+
+	decoded := &Bloom{}
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, f.Len(), decoded.Len())
+	assert.Equal(t, f.K(), decoded.K())
+	assert.Equal(t, f.Seed(), decoded.Seed())
+	assert.True(t, decoded.TestString("x"))
+	assert.True(t, decoded.TestString("y"))
+	assert.NoError(t, decoded.Union(f))
+
+	invalid := map[string][]byte{
+		"empty":    {},
+		"version":  {2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1},
+		"zero_k":   {1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1},
+		"huge_k":   {1, 0x80, 0x80, 0x80, 0x80, 0x80, 0x20, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1},
+		"k_65":     {1, 65, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1},
+		"huge_len": {1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		"no_seed":  {1, 1, 0, 0},
+		"no_bits":  {1, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+		"zero_len": {1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0},
+		"bits":     {1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1, 9},
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, errors.Is(decoded.UnmarshalBinary(data), ErrInvalidEncoding))
+		})
+	}
+}
+
+func TestNewBloomPanics(t *testing.T) {
+	assert.Panics(t, func() { NewBloom(10, 0, 0) })
+	assert.Panics(t, func() { NewBloom(10, 1, 0) })
+	assert.NotPanics(t, func() { NewBloom(0, 0.5, 0) })
+	assert.Equal(t, uint(bloomMaxK), NewBloom(10, 1e-30, 0).K())
+}
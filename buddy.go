@@ -0,0 +1,112 @@
+package bitmask
+
+import "fmt"
+
+// Buddy allocator of an arena of 2^maxOrder units. Block of order k has the size of 2^k units and is aligned to it.
+// Allocation splits bigger blocks in halves (buddies) when necessary, and freeing merges free buddies back.
+//
+// Free blocks of each order are tracked by a separate bitmask level (set bit means free block),
+// allocated blocks are tracked in the same way, so that Free can validate its arguments in O(1).
+// All the levels are slices of a single backing bitmask of 2*(2^(maxOrder+1)-1) bits.
+// Not safe for concurrent use.
+type BuddyAllocator struct {
+	// free blocks of each order, levels[k] has 2^(maxOrder-k) bits
+	levels []*BitMask
+	// allocated blocks of each order, laid out in the same way as levels
+	allocated []*BitMask
+	maxOrder  uint
+}
+
+// Creates buddy allocator of an arena of 2^maxOrder units, all free.
+func NewBuddyAllocator(maxOrder uint) *BuddyAllocator {
+	if maxOrder >= uintSize-1 {
+		panic(fmt.Sprintf("max order %v is too big", maxOrder))
+	}
+	backing := New(2 * (1<<(maxOrder+1) - 1))
+	levels := make([]*BitMask, maxOrder+1)
+	allocated := make([]*BitMask, maxOrder+1)
+	start := uint(0)
+	for _, orderLevels := range [][]*BitMask{levels, allocated} {
+		for order := uint(0); order <= maxOrder; order++ {
+			levelLen := uint(1) << (maxOrder - order)
+			orderLevels[order] = backing.Slice(start, start+levelLen)
+			start += levelLen
+		}
+	}
+	// the whole arena is a single free block
+	levels[maxOrder].Set(0)
+	return &BuddyAllocator{levels: levels, allocated: allocated, maxOrder: maxOrder}
+}
+
+// Returns the order of the whole arena.
+func (a *BuddyAllocator) MaxOrder() uint {
+	return a.maxOrder
+}
+
+// Allocates a block of 2^order units, returning its offset (in units).
+// If there's no free block of this order, and it can't be split from a bigger one, ok will be false.
+func (a *BuddyAllocator) Alloc(order uint) (offset uint, ok bool) {
+	a.checkOrder(order)
+	for k := order; k <= a.maxOrder; k++ {
+		index, found := a.levels[k].First()
+		if !found {
+			continue
+		}
+		a.levels[k].Clear(index)
+		// split, keeping the first half and releasing the second one
+		for ; k > order; k-- {
+			index *= 2
+			a.levels[k-1].Set(index + 1)
+		}
+		a.allocated[order].Set(index)
+		return index << order, true
+	}
+	return 0, false
+}
+
+// Releases a block of 2^order units, allocated at offset, merging it with its free buddies.
+// Panics if the offset is not aligned to the block size, or if the block of this order wasn't allocated at offset
+// (e.g. it's already free).
+func (a *BuddyAllocator) Free(offset uint, order uint) {
+	a.checkOrder(order)
+	if offset&(1<<order-1) != 0 {
+		panic(fmt.Sprintf("offset %v is not aligned to the block of order %v", offset, order))
+	}
+	index := offset >> order
+	checkBounds(a.levels[order].len, index)
+	if !a.allocated[order].IsSet(index) {
+		panic(fmt.Sprintf("block at offset %v of order %v is not allocated", offset, order))
+	}
+	a.allocated[order].Clear(index)
+
+	for ; order < a.maxOrder; order++ {
+		buddy := index ^ 1
+		if !a.levels[order].IsSet(buddy) {
+			break
+		}
+		a.levels[order].Clear(buddy)
+		index >>= 1
+	}
+	a.levels[order].Set(index)
+}
+
+// Returns the number of free blocks of the order, not counting the ones which can be produced by splitting bigger blocks.
+func (a *BuddyAllocator) FreeBlocks(order uint) uint {
+	a.checkOrder(order)
+	return a.levels[order].Count()
+}
+
+// Returns the total number of free units.
+func (a *BuddyAllocator) FreeUnits() uint {
+	free := uint(0)
+	for order, level := range a.levels {
+		free += level.Count() << order
+	}
+	return free
+}
+
+func (a *BuddyAllocator) checkOrder(order uint) {
+	if order > a.maxOrder {
+		panic(fmt.Sprintf("order %v is out of range with max order %v", order, a.maxOrder))
+	}
+}
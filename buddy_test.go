@@ -0,0 +1,120 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuddyAllocator(t *testing.T) {
+	a := NewBuddyAllocator(4)
+	assert.Equal(t, uint(16), a.FreeUnits())
+	assert.Equal(t, uint(1), a.FreeBlocks(4))
+
+	offset, ok := a.Alloc(0)
+	assert.True(t, ok)
+	assert.Equal(t, uint(0), offset)
+	// 16 was split into 8+4+2+1+1
+	assert.Equal(t, []uint{1, 1, 1, 1, 0}, freeBlocksPerOrder(a))
+
+	offset, ok = a.Alloc(2)
+	assert.True(t, ok)
+	assert.Equal(t, uint(4), offset)
+
+	offset, ok = a.Alloc(2)
+	assert.True(t, ok)
+	assert.Equal(t, uint(8), offset)
+	assert.Equal(t, []uint{1, 1, 1, 0, 0}, freeBlocksPerOrder(a))
+	assert.Equal(t, uint(16-1-4-4), a.FreeUnits())
+
+	_, ok = a.Alloc(4)
+	assert.False(t, ok)
+
+	// merging back
+	a.Free(0, 0)
+	a.Free(8, 2)
+	a.Free(4, 2)
+	assert.Equal(t, []uint{0, 0, 0, 0, 1}, freeBlocksPerOrder(a))
+
+	assert.Panics(t, func() { a.Free(4, 2) })
+	assert.Panics(t, func() { a.Free(3, 2) })
+	assert.Panics(t, func() { a.Alloc(5) })
+}
+
+func TestBuddyAllocatorFreePartiallyFree(t *testing.T) {
+	a := NewBuddyAllocator(3)
+	offset, ok := a.Alloc(0)
+	assert.True(t, ok)
+	assert.Equal(t, uint(0), offset)
+
+	// unit 1 is free, so the block [0, 2) can't be freed as a whole
+	assert.Panics(t, func() { a.Free(0, 1) })
+	assert.Panics(t, func() { a.Free(0, 3) })
+	assert.Equal(t, uint(7), a.FreeUnits())
+
+	a.Free(0, 0)
+	assert.Equal(t, []uint{0, 0, 0, 1}, freeBlocksPerOrder(a))
+
+	// a block can only be freed with the order it was allocated with
+	offset, ok = a.Alloc(2)
+	assert.True(t, ok)
+	assert.Equal(t, uint(0), offset)
+	assert.Panics(t, func() { a.Free(0, 1) })
+	assert.Panics(t, func() { a.Free(0, 0) })
+	assert.Equal(t, uint(4), a.FreeUnits())
+	a.Free(0, 2)
+	assert.Equal(t, []uint{0, 0, 0, 1}, freeBlocksPerOrder(a))
+}
+
+func freeBlocksPerOrder(a *BuddyAllocator) []uint {
+	result := []uint{}
+	for order := uint(0); order <= a.MaxOrder(); order++ {
+		result = append(result, a.FreeBlocks(order))
+	}
+	return result
+}
+
+func TestBuddyAllocatorRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	maxOrder := uint(10)
+	a := NewBuddyAllocator(maxOrder)
+	type allocation struct{ offset, order uint }
+	allocations := []allocation{}
+	owners := make([]int, 1<<maxOrder)
+
+	for i := range 5000 {
+		if rnd.Intn(2) == 0 {
+			order := uint(rnd.Intn(5))
+			offset, ok := a.Alloc(order)
+			if !ok {
+				continue
+			}
+			assert.Equal(t, uint(0), offset%(1<<order))
+			for j := offset; j < offset+1<<order; j++ {
+				assert.Equal(t, 0, owners[j], "unit %v is allocated twice", j)
+				owners[j] = i + 1
+			}
+			allocations = append(allocations, allocation{offset, order})
+		} else if len(allocations) > 0 {
+			k := rnd.Intn(len(allocations))
+			al := allocations[k]
+			allocations = append(allocations[:k], allocations[k+1:]...)
+			a.Free(al.offset, al.order)
+			for j := al.offset; j < al.offset+1<<al.order; j++ {
+				owners[j] = 0
+			}
+		}
+
+		used := uint(0)
+		for _, al := range allocations {
+			used += 1 << al.order
+		}
+		assert.Equal(t, uint(1<<maxOrder)-used, a.FreeUnits())
+	}
+
+	for _, al := range allocations {
+		a.Free(al.offset, al.order)
+	}
+	assert.Equal(t, uint(1), a.FreeBlocks(maxOrder))
+}
@@ -0,0 +1,82 @@
+package bitmask
+
+// Returns true if both bitmasks have the same length and the same bits set.
+// Bitmasks may be slices with different offsets.
+func Equal(a *BitMask, b *BitMask) bool {
+	if a.len != b.len {
+		return false
+	}
+	for i := uint(0); i < a.len; i += uintSize {
+		if a.loadWord(i) != b.loadWord(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compares bitmasks lexicographically, bit by bit starting from index 0: the first differing bit decides
+// (cleared bit is less than set bit), and if one bitmask is a prefix of the other, the shorter one is less.
+// The result will be 0 if a == b, -1 if a < b, and +1 if a > b. Can be used with slices.SortFunc.
+func Compare(a *BitMask, b *BitMask) int {
+	commonLen := minUint(a.len, b.len)
+	for i := uint(0); i < commonLen; i += uintSize {
+		mask := uintMax << (uintSize - minUint(uintSize, commonLen-i))
+		aw, bw := a.loadWord(i)&mask, b.loadWord(i)&mask
+		if aw < bw {
+			return -1
+		}
+		if aw > bw {
+			return 1
+		}
+	}
+	if a.len < b.len {
+		return -1
+	}
+	if a.len > b.len {
+		return 1
+	}
+	return 0
+}
+
+// Returns true if all bits set in bm are also set in other.
+// If lengths are different, missing bits of the shorter bitmask are considered cleared.
+func (bm *BitMask) IsSubsetOf(other *BitMask) bool {
+	return !anyWord(bm, other, func(x, y uint) bool { return x&^y != 0 })
+}
+
+// Returns true if all bits set in other are also set in bm.
+// If lengths are different, missing bits of the shorter bitmask are considered cleared.
+func (bm *BitMask) IsSupersetOf(other *BitMask) bool {
+	return other.IsSubsetOf(bm)
+}
+
+// Returns true if there's at least one bit set in both bitmasks.
+func (bm *BitMask) Intersects(other *BitMask) bool {
+	return anyWord(bm, other, func(x, y uint) bool { return x&y != 0 })
+}
+
+// Returns true if there're no bits set in both bitmasks. Opposite of Intersects.
+func (bm *BitMask) Disjoint(other *BitMask) bool {
+	return !bm.Intersects(other)
+}
+
+// returns true if predicate is true for at least one pair of words, stops at the first one
+func anyWord(a *BitMask, b *BitMask, predicate func(x, y uint) bool) bool {
+	maxLen := a.len
+	if b.len > maxLen {
+		maxLen = b.len
+	}
+	for i := uint(0); i < maxLen; i += uintSize {
+		var aw, bw uint
+		if i < a.len {
+			aw = a.loadWord(i)
+		}
+		if i < b.len {
+			bw = b.loadWord(i)
+		}
+		if predicate(aw, bw) {
+			return true
+		}
+	}
+	return false
+}
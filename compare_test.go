@@ -0,0 +1,98 @@
+package bitmask
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// returns all the bits as "0101..." string, without skipping
+func bitString(bm *BitMask) string {
+	var b strings.Builder
+	for _, isSet := range bm.Bits() {
+		if isSet {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+func TestEqualCompare(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 500 {
+		n := uint(rnd.Intn(3 * uintSize))
+		a := randomBitMask(rnd, n)
+		var b *BitMask
+		switch rnd.Intn(3) {
+		case 0:
+			// equal, but with different offset
+			b, _ = randomSlice(rnd, uintSize, n)
+			Copy(b, a)
+		case 1:
+			// one bit difference
+			b = a.clone()
+			if n > 0 {
+				b.Toggle(uint(rnd.Intn(int(n))))
+			}
+		default:
+			// different length
+			b = randomBitMask(rnd, uint(rnd.Intn(3*uintSize)))
+			Copy(b, a)
+		}
+
+		aStr, bStr := bitString(a), bitString(b)
+		assert.Equal(t, aStr == bStr, Equal(a, b))
+		assert.Equal(t, strings.Compare(aStr, bStr), Compare(a, b), "%v vs %v", aStr, bStr)
+		assert.Equal(t, strings.Compare(bStr, aStr), Compare(b, a))
+	}
+}
+
+func TestCompareSort(t *testing.T) {
+	masks := []*BitMask{
+		NewFromUint(0b11).Slice(0, 3),
+		NewFromUint(0b01).Slice(0, 2),
+		NewFromUint(0b11).Slice(0, 2),
+		New(0),
+		NewFromUint(0b10).Slice(0, 2),
+	}
+	slices.SortFunc(masks, Compare)
+
+	actual := []string{}
+	for _, bm := range masks {
+		actual = append(actual, bm.String())
+	}
+	assert.Equal(t, []string{"[0]{}", "[2]{01}", "[2]{10}", "[2]{11}", "[3]{110}"}, actual)
+}
+
+func TestSubsetIntersects(t *testing.T) {
+	tests := map[string]struct {
+		a, b                         *BitMask
+		subset, superset, intersects bool
+	}{
+		"empty":              {New(0), New(0), true, true, false},
+		"empty_and_clear":    {New(0), New(10), true, true, false},
+		"empty_and_set":      {New(0), NewFromUint(1), true, false, false},
+		"equal":              {NewFromUint(0b101), NewFromUint(0b101), true, true, true},
+		"subset":             {NewFromUint(0b100), NewFromUint(0b101), true, false, true},
+		"superset":           {NewFromUint(0b111, 1), NewFromUint(0b101), false, true, true},
+		"disjoint":           {NewFromUint(0b1010), NewFromUint(0b0101), false, false, false},
+		"longer_clear_tail":  {NewFromUint(0b1, 0), NewFromUint(0b11), true, false, true},
+		"sliced_subset":      {NewFromUint(uintMax, 0b1).Slice(uintSize-1, uintSize+1), NewFromUint(0b11), true, true, true},
+		"sliced_intersects":  {NewFromUint(0, 1<<3).Slice(3, 2*uintSize), NewFromUint(0, 1).Slice(3, 2*uintSize), false, false, false},
+		"sliced_intersects2": {NewFromUint(0, 1<<3).Slice(3, 2*uintSize), NewFromUint(0, 1<<3).Slice(3, 2*uintSize), true, true, true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.subset, tc.a.IsSubsetOf(tc.b))
+			assert.Equal(t, tc.superset, tc.a.IsSupersetOf(tc.b))
+			assert.Equal(t, tc.intersects, tc.a.Intersects(tc.b))
+			assert.Equal(t, !tc.intersects, tc.a.Disjoint(tc.b))
+			assert.Equal(t, tc.intersects, tc.b.Intersects(tc.a))
+		})
+	}
+}
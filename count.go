@@ -0,0 +1,53 @@
+package bitmask
+
+import "math/bits"
+
+// Returns the number of set bits (population count).
+func (bm *BitMask) Count() uint {
+	if bm.len == 0 {
+		return 0
+	}
+	count := 0
+	for i := 0; i < len(bm.store); i++ {
+		count += bits.OnesCount(bm.store[i] & bm.getStoreWordMask(i))
+	}
+	return uint(count)
+}
+
+// Returns the number of set bits in a half-open range, which includes the "from" bit, but excludes the "to" one.
+// Equivalent of Slice(fromBit, toBit).Count().
+func (bm *BitMask) CountRange(fromBit uint, toBit uint) uint {
+	return bm.Slice(fromBit, toBit).Count()
+}
+
+// Returns true if at least one bit is set. Stops at the first non-empty word.
+func (bm *BitMask) Any() bool {
+	if bm.len == 0 {
+		return false
+	}
+	for i := 0; i < len(bm.store); i++ {
+		if bm.store[i]&bm.getStoreWordMask(i) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if no bits are set. Opposite of Any. Empty bitmask has no bits set.
+func (bm *BitMask) None() bool {
+	return !bm.Any()
+}
+
+// Returns true if all bits are set. Stops at the first word with a cleared bit. Empty bitmask has all bits set.
+func (bm *BitMask) All() bool {
+	if bm.len == 0 {
+		return true
+	}
+	for i := 0; i < len(bm.store); i++ {
+		mask := bm.getStoreWordMask(i)
+		if bm.store[i]&mask != mask {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func naiveCount(bm *BitMask) uint {
+	count := uint(0)
+	for _, isSet := range bm.Bits() {
+		if isSet {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCount(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 200 {
+		n := uint(rnd.Intn(5 * uintSize))
+		bm, _ := randomSlice(rnd, 2*uintSize, n)
+		from := uint(rnd.Intn(int(n + 1)))
+		to := from + uint(rnd.Intn(int(n-from+1)))
+
+		assert.Equal(t, naiveCount(bm), bm.Count())
+		assert.Equal(t, naiveCount(bm.Slice(from, to)), bm.CountRange(from, to))
+	}
+}
+
+func TestCountSliceIgnoresNeighbours(t *testing.T) {
+	bm := NewFromUint(uintMax, uintMax, uintMax)
+	slice := bm.Slice(3, 2*uintSize+1)
+	slice.ClearAll()
+	slice.Set(0)
+	slice.Set(uintSize)
+
+	assert.Equal(t, uint(2), slice.Count())
+	assert.Equal(t, uint(2+3+uintSize-1), bm.Count())
+}
+
+func TestAnyNoneAll(t *testing.T) {
+	tests := map[string]struct {
+		source                   *BitMask
+		expectedAny, expectedAll bool
+	}{
+		"empty":            {New(0), false, true},
+		"1b_clear":         {New(1), false, false},
+		"1w_set":           {NewFromUint(uintMax), true, true},
+		"1w_one":           {NewFromUint(1 << 10), true, false},
+		"3w_slice_clear":   {NewFromUint(1, 0, 1<<1).Slice(1, 2*uintSize+1), false, false},
+		"3w_slice_set":     {NewFromUint(uintMax-1, uintMax, 1).Slice(1, 2*uintSize+1), true, true},
+		"3w_slice_one_set": {NewFromUint(uintMax-1, 0, 1).Slice(1, 2*uintSize+1), true, false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedAny, tc.source.Any())
+			assert.Equal(t, !tc.expectedAny, tc.source.None())
+			assert.Equal(t, tc.expectedAll, tc.source.All())
+		})
+	}
+}
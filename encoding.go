@@ -0,0 +1,106 @@
+package bitmask
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+const binaryFormatVersion = 1
+
+// Returned when decoding data, which wasn't produced by this package (or is corrupted).
+var ErrInvalidEncoding = errors.New("bitmask: invalid encoding")
+
+// Implements encoding.BinaryMarshaler. See AppendBinary for the format description.
+func (bm *BitMask) MarshalBinary() ([]byte, error) {
+	return bm.AppendBinary(nil)
+}
+
+// Implements encoding.BinaryAppender. Appends platform-independent binary representation of a bitmask to b.
+//
+// Format: version byte (1), uvarint number of bits, followed by bits packed in bytes,
+// so that bit i is stored in byte i/8 as 1<<(i%8). Unused bits of the last byte are zero.
+// Only Len() bits are encoded, so a sliced bitmask is encoded in the same way as its copy.
+func (bm *BitMask) AppendBinary(b []byte) ([]byte, error) {
+	b = append(b, binaryFormatVersion)
+	b = binary.AppendUvarint(b, uint64(bm.len))
+	return bm.appendBytes(b, LSBFirst), nil
+}
+
+// Implements encoding.BinaryUnmarshaler. Replaces receiver with the decoded bitmask,
+// so if it was a slice, it will not share the buffer with the original bitmask anymore.
+func (bm *BitMask) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty data", ErrInvalidEncoding)
+	}
+	if data[0] != binaryFormatVersion {
+		return fmt.Errorf("%w: unsupported version %v", ErrInvalidEncoding, data[0])
+	}
+	bitsN, n := binary.Uvarint(data[1:])
+	if n <= 0 || bitsN > uint64(uintMax) {
+		return fmt.Errorf("%w: invalid length", ErrInvalidEncoding)
+	}
+	data = data[1+n:]
+	// checking the upper bound first, so that bitsN+7 doesn't overflow
+	if bitsN > uint64(len(data))*8 || uint64(len(data)) != (bitsN+7)/8 {
+		return fmt.Errorf("%w: expected %v bytes for %v bits, got %v", ErrInvalidEncoding, (bitsN+7)/8, bitsN, len(data))
+	}
+	*bm = *newFromBytes(data, uint(bitsN), LSBFirst)
+	return nil
+}
+
+// Creates new bitmask of len(b)*8 bits, unpacked from bytes in the given order:
+// for MSBFirst bit i is 1<<(7-i%8) of byte i/8, and for LSBFirst it's 1<<(i%8)
+// (the same as numpy.unpackbits with bitorder "big" and "little" respectively).
+func NewFromBytes(b []byte, order BitOrder) *BitMask {
+	return newFromBytes(b, uint(len(b))*8, order)
+}
+
+// Returns bits packed in bytes in the given order (see NewFromBytes). Unused bits of the last byte are zero.
+func (bm *BitMask) Bytes(order BitOrder) []byte {
+	return bm.appendBytes(make([]byte, 0, (bm.len+7)/8), order)
+}
+
+// Appends bits packed in bytes in the given order (see NewFromBytes) to dst and returns the extended slice.
+// Unused bits of the last byte are zero.
+func (bm *BitMask) AppendBytes(dst []byte, order BitOrder) []byte {
+	return bm.appendBytes(dst, order)
+}
+
+// appends bits packed in bytes, bit i is stored in byte i/8 as 1<<(i%8) for LSBFirst, or as 1<<(7-i%8) for MSBFirst
+func (bm *BitMask) appendBytes(b []byte, order BitOrder) []byte {
+	for i := uint(0); i < bm.len; i += uintSize {
+		// bit i is the most significant one
+		w := bm.loadWord(i)
+		if order == LSBFirst {
+			// reverse bits of each byte
+			w = bits.ReverseBytes(bits.Reverse(w))
+		}
+		bytesN := (minUint(uintSize, bm.len-i) + 7) / 8
+		for j := uint(0); j < bytesN; j++ {
+			b = append(b, byte(w>>(uintSize-8-8*j)))
+		}
+	}
+	return b
+}
+
+// opposite of appendBytes, bits after len are ignored
+func newFromBytes(data []byte, len uint, order BitOrder) *BitMask {
+	bm := New(len)
+	for i, v := range data {
+		bitIndex := uint(i) * 8
+		if bitIndex >= len {
+			break
+		}
+		if order == LSBFirst {
+			v = bits.Reverse8(v)
+		}
+		bm.store[bitIndex/uintSize] |= uint(v) << (uintSize - 8 - bitIndex%uintSize)
+	}
+	if len > 0 {
+		// clear padding bits
+		bm.store[bm.LenUint()-1] &= bm.getStoreWordMask(bm.LenUint() - 1)
+	}
+	return bm
+}
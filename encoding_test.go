@@ -0,0 +1,120 @@
+package bitmask
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 200 {
+		bm, _ := randomSlice(rnd, 2*uintSize, uint(rnd.Intn(5*uintSize)))
+
+		data, err := bm.MarshalBinary()
+		assert.NoError(t, err)
+
+		copied := bm.clone()
+		copiedData, err := copied.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, copiedData, data)
+
+		decoded := New(10)
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.True(t, Equal(bm, decoded))
+	}
+}
+
+func TestBinaryFormat(t *testing.T) {
+	bm := New(10)
+	bm.Set(0)
+	bm.Set(3)
+	bm.Set(9)
+
+	data, err := bm.AppendBinary([]byte{0xff})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 1, 10, 0b00001001, 0b00000010}, data)
+
+	data, err = New(0).MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0}, data)
+}
+
+func TestBinaryInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":           {},
+		"unknown_version": {2, 0},
+		"no_length":       {1},
+		"missing_bytes":   {1, 10, 0},
+		"extra_bytes":     {1, 1, 0, 0},
+		"bad_uvarint":     {1, 0xff},
+		"max_length":      {1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		"huge_length":     {1, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			bm := New(0)
+			assert.ErrorIs(t, bm.UnmarshalBinary(data), ErrInvalidEncoding)
+		})
+	}
+}
+
+func TestBinaryIgnoresPadding(t *testing.T) {
+	bm := New(0)
+	assert.NoError(t, bm.UnmarshalBinary([]byte{1, 3, 0xff}))
+	assert.Equal(t, "[3]{111}", bm.String())
+}
+
+func TestGob(t *testing.T) {
+	type record struct {
+		Name string
+		Mask *BitMask
+	}
+	bm := NewFromUint(0b1011, 1).Slice(1, uintSize+3)
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(record{"test", bm}))
+
+	var decoded record
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+	assert.Equal(t, "test", decoded.Name)
+	assert.True(t, Equal(bm, decoded.Mask))
+}
+
+func TestBytes(t *testing.T) {
+	// numpy.packbits([1, 0, 1, 1, 0, 0, 0, 0, 0, 1, 1], bitorder="big") == [176, 96]
+	// numpy.packbits([1, 0, 1, 1, 0, 0, 0, 0, 0, 1, 1], bitorder="little") == [13, 6]
+	bm := New(11)
+	for _, i := range []uint{0, 2, 3, 9, 10} {
+		bm.Set(i)
+	}
+	assert.Equal(t, []byte{176, 96}, bm.Bytes(MSBFirst))
+	assert.Equal(t, []byte{13, 6}, bm.Bytes(LSBFirst))
+	assert.Equal(t, []byte{0xff, 176, 96}, bm.AppendBytes([]byte{0xff}, MSBFirst))
+	assert.Equal(t, []byte{}, New(0).Bytes(MSBFirst))
+
+	assert.Equal(t, "1011000001100000", bitString(NewFromBytes([]byte{176, 96}, MSBFirst)))
+	assert.Equal(t, "1011000001100000", bitString(NewFromBytes([]byte{13, 6}, LSBFirst)))
+	assert.Equal(t, uint(0), NewFromBytes(nil, LSBFirst).Len())
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		for range 200 {
+			bm, _ := randomSlice(rnd, 2*uintSize, uint(rnd.Intn(3*uintSize)))
+
+			data := bm.Bytes(order)
+			assert.Equal(t, int(bm.Len()+7)/8, len(data))
+
+			decoded := NewFromBytes(data, order)
+			assert.Equal(t, uint(len(data))*8, decoded.Len())
+			assert.Equal(t, bitString(bm), bitString(decoded.Slice(0, bm.Len())))
+			// padding is zero
+			assert.True(t, decoded.Slice(bm.Len(), decoded.Len()).None())
+		}
+	}
+}
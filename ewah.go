@@ -0,0 +1,277 @@
+package bitmask
+
+import (
+	"iter"
+	"math/bits"
+)
+
+const ewahRunLenBits = uintSize / 2
+const ewahMaxRunLen = uint(1)<<ewahRunLenBits - 1
+const ewahMaxLiterals = uint(1)<<(uintSize-1-ewahRunLenBits) - 1
+
+// Enhanced Word-Aligned Hybrid compressed bitmask, efficient for data with long runs of cleared or set bits.
+//
+// Bits are split into uint words, and sequences of "clean" words (with all bits cleared or all bits set)
+// are replaced by a marker word, which stores the value of clean words (the lowest bit),
+// their number (next uintSize/2 bits) and the number of "dirty" (literal) words following them (the rest of the bits).
+//
+// Set operations are performed directly on compressed data, without decompressing it.
+// EWAH is immutable, operations are returning new instances.
+type EWAH struct {
+	// marker words followed by literal words, starting from the marker
+	words []uint
+	// index of the last marker in words
+	lastMarker int
+	// number of bits
+	len uint
+}
+
+// Creates compressed copy of a bitmask. Bitmask may be a slice.
+func NewEWAH(bm *BitMask) *EWAH {
+	e := newEmptyEWAH(bm.len)
+	for i := uint(0); i < bm.len; i += uintSize {
+		e.addLiteral(bm.loadWord(i))
+	}
+	return e
+}
+
+func newEmptyEWAH(len uint) *EWAH {
+	return &EWAH{words: []uint{0}, len: len}
+}
+
+// Returns the length of compressed bitmask in bits, which is the length of the original bitmask.
+func (e *EWAH) Len() uint {
+	return e.len
+}
+
+// Returns the length of compressed data in uints.
+func (e *EWAH) LenUint() int {
+	return len(e.words)
+}
+
+// Creates decompressed bitmask.
+func (e *EWAH) BitMask() *BitMask {
+	bm := New(e.len)
+	wordIndex := uint(0)
+	for s := range e.segments() {
+		if s.runBit == 1 {
+			bm.Slice(wordIndex*uintSize, minUint((wordIndex+s.runLen)*uintSize, e.len)).SetAll()
+		}
+		wordIndex += s.runLen
+		wordIndex += uint(copy(bm.store[wordIndex:], s.literals))
+	}
+	return bm
+}
+
+// Returns the number of set bits.
+func (e *EWAH) Count() uint {
+	count := uint(0)
+	for s := range e.segments() {
+		count += s.runBit * s.runLen * uintSize
+		for _, w := range s.literals {
+			count += uint(bits.OnesCount(w))
+		}
+	}
+	return count
+}
+
+// Go >=1.23 iterator through the indexes of set bits, in ascending order. Skips runs of cleared bits without decompressing.
+func (e *EWAH) SetBits() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		bitIndex := uint(0)
+		for s := range e.segments() {
+			if s.runBit == 1 {
+				for i, end := bitIndex, minUint(bitIndex+s.runLen*uintSize, e.len); i < end; i++ {
+					if !yield(i) {
+						return
+					}
+				}
+			}
+			bitIndex += s.runLen * uintSize
+			for _, w := range s.literals {
+				for w != 0 {
+					lz := uint(bits.LeadingZeros(w))
+					if !yield(bitIndex + lz) {
+						return
+					}
+					w &^= oneInBE >> lz
+				}
+				bitIndex += uintSize
+			}
+		}
+	}
+}
+
+// Returns bitwise AND of compressed bitmasks, computed without decompression.
+// Bitmasks of different lengths are aligned by the first bit, missing bits of the shorter one are considered cleared,
+// and the result has the length of the longer one.
+func (e *EWAH) And(other *EWAH) *EWAH {
+	return ewahOp(e, other, func(x, y uint) uint { return x & y })
+}
+
+// Returns bitwise OR of compressed bitmasks, computed without decompression. See And for the details about lengths.
+func (e *EWAH) Or(other *EWAH) *EWAH {
+	return ewahOp(e, other, func(x, y uint) uint { return x | y })
+}
+
+// Returns bitwise XOR of compressed bitmasks, computed without decompression. See And for the details about lengths.
+func (e *EWAH) Xor(other *EWAH) *EWAH {
+	return ewahOp(e, other, func(x, y uint) uint { return x ^ y })
+}
+
+// Returns bitwise AND NOT of compressed bitmasks, computed without decompression. See And for the details about lengths.
+func (e *EWAH) AndNot(other *EWAH) *EWAH {
+	return ewahOp(e, other, func(x, y uint) uint { return x &^ y })
+}
+
+// marker with its literal words
+type ewahSegment struct {
+	// value of clean words, 0 or 1
+	runBit uint
+	// number of clean words
+	runLen   uint
+	literals []uint
+}
+
+// iterates through the markers
+func (e *EWAH) segments() iter.Seq[ewahSegment] {
+	return func(yield func(ewahSegment) bool) {
+		for i := 0; i < len(e.words); {
+			marker := e.words[i]
+			literalsN := int(ewahLiterals(marker))
+			if !yield(ewahSegment{marker & 1, ewahRunLen(marker), e.words[i+1 : i+1+literalsN]}) {
+				return
+			}
+			i += 1 + literalsN
+		}
+	}
+}
+
+// appends n clean words, w must be 0 or uintMax
+func (e *EWAH) addClean(w uint, n uint) {
+	runBit := w & 1
+	for n > 0 {
+		marker := e.words[e.lastMarker]
+		runLen := ewahRunLen(marker)
+		if ewahLiterals(marker) != 0 || (runLen != 0 && marker&1 != runBit) || runLen == ewahMaxRunLen {
+			e.lastMarker = len(e.words)
+			e.words = append(e.words, 0)
+			marker, runLen = 0, 0
+		}
+		added := minUint(n, ewahMaxRunLen-runLen)
+		e.words[e.lastMarker] = runBit | (runLen+added)<<1
+		n -= added
+	}
+}
+
+// appends literal word, which may be converted to the clean one
+func (e *EWAH) addLiteral(w uint) {
+	if w == 0 || w == uintMax {
+		e.addClean(w, 1)
+		return
+	}
+	literalsN := ewahLiterals(e.words[e.lastMarker])
+	if literalsN == ewahMaxLiterals {
+		e.lastMarker = len(e.words)
+		e.words = append(e.words, 0)
+		literalsN = 0
+	}
+	e.words[e.lastMarker] = e.words[e.lastMarker]&(1<<(1+ewahRunLenBits)-1) | (literalsN+1)<<(1+ewahRunLenBits)
+	e.words = append(e.words, w)
+}
+
+func ewahRunLen(marker uint) uint {
+	return (marker >> 1) & ewahMaxRunLen
+}
+
+func ewahLiterals(marker uint) uint {
+	return marker >> (1 + ewahRunLenBits)
+}
+
+// sequential reader of compressed words
+type ewahReader struct {
+	words []uint
+	// index of the next marker
+	nextMarker int
+	// value of clean words, 0 or uintMax
+	runWord uint
+	// number of clean words left
+	runLen uint
+	// literal words left
+	literals []uint
+}
+
+// makes sure there're words to read, after the end of data, reads infinite clean cleared words
+func (r *ewahReader) load() {
+	for r.runLen == 0 && len(r.literals) == 0 {
+		if r.nextMarker >= len(r.words) {
+			r.runWord, r.runLen = 0, uintMax
+			return
+		}
+		marker := r.words[r.nextMarker]
+		literalsN := int(ewahLiterals(marker))
+		r.runWord = 0 - marker&1
+		r.runLen = ewahRunLen(marker)
+		r.literals = r.words[r.nextMarker+1 : r.nextMarker+1+literalsN]
+		r.nextMarker += 1 + literalsN
+	}
+}
+
+func (r *ewahReader) skip(n uint) {
+	if r.runLen > 0 {
+		r.runLen -= n
+	} else {
+		r.literals = r.literals[n:]
+	}
+}
+
+// number of words of the same kind (clean or literal) available
+func (r *ewahReader) available() uint {
+	if r.runLen > 0 {
+		return r.runLen
+	}
+	return uint(len(r.literals))
+}
+
+func ewahOp(a *EWAH, b *EWAH, op func(x, y uint) uint) *EWAH {
+	result := newEmptyEWAH(max(a.len, b.len))
+	wordsN := (result.len + uintSize - 1) / uintSize
+	ra, rb := &ewahReader{words: a.words}, &ewahReader{words: b.words}
+
+	for done := uint(0); done < wordsN; {
+		ra.load()
+		rb.load()
+		n := min(ra.available(), rb.available(), wordsN-done)
+
+		switch {
+		case ra.runLen > 0 && rb.runLen > 0:
+			result.addClean(op(ra.runWord, rb.runWord), n)
+		case ra.runLen > 0:
+			if allCleared, allSet := op(ra.runWord, 0), op(ra.runWord, uintMax); allCleared == allSet {
+				// result doesn't depend on literals
+				result.addClean(allCleared, n)
+			} else {
+				for _, w := range rb.literals[:n] {
+					result.addLiteral(op(ra.runWord, w))
+				}
+			}
+		case rb.runLen > 0:
+			if allCleared, allSet := op(0, rb.runWord), op(uintMax, rb.runWord); allCleared == allSet {
+				result.addClean(allCleared, n)
+			} else {
+				for _, w := range ra.literals[:n] {
+					result.addLiteral(op(w, rb.runWord))
+				}
+			}
+		default:
+			for i := uint(0); i < n; i++ {
+				result.addLiteral(op(ra.literals[i], rb.literals[i]))
+			}
+		}
+
+		ra.skip(n)
+		rb.skip(n)
+		done += n
+	}
+	return result
+}
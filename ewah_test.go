@@ -0,0 +1,100 @@
+package bitmask
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generates bitmask with long runs of cleared and set bits
+func randomRunsBitMask(rnd *rand.Rand, n uint) *BitMask {
+	bm := New(n)
+	for i := uint(0); i < n; {
+		runLen := minUint(uint(1+rnd.Intn(5*uintSize)), n-i)
+		switch rnd.Intn(3) {
+		case 0:
+			bm.Slice(i, i+runLen).SetAll()
+		case 1:
+			for j := i; j < i+runLen; j++ {
+				if rnd.Intn(2) == 0 {
+					bm.Set(j)
+				}
+			}
+		}
+		i += runLen
+	}
+	return bm
+}
+
+func TestEWAHRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 200 {
+		n := uint(rnd.Intn(30 * uintSize))
+		bm, _ := randomSlice(rnd, uintSize, n)
+		Copy(bm, randomRunsBitMask(rnd, n))
+
+		e := NewEWAH(bm)
+		assert.Equal(t, n, e.Len())
+		assert.True(t, Equal(bm, e.BitMask()))
+		assert.Equal(t, bm.Count(), e.Count())
+		assert.Equal(t, slices.Collect(bm.SetBits()), slices.Collect(e.SetBits()))
+	}
+}
+
+func TestEWAHCompression(t *testing.T) {
+	bm := New(1000 * uintSize)
+	bm.Slice(100*uintSize, 600*uintSize).SetAll()
+	bm.Set(700*uintSize + 1)
+
+	e := NewEWAH(bm)
+	// [marker: 100 clean cleared words] [marker: 500 clean set words] [marker: 100 clean cleared words, 1 literal] [literal] [marker: 299 clean cleared words]
+	assert.Equal(t, 5, e.LenUint())
+	assert.True(t, Equal(bm, e.BitMask()))
+}
+
+func TestEWAHLongRuns(t *testing.T) {
+	// run exceeding the capacity of a single marker
+	runLen := ewahMaxRunLen + 10
+	e := newEmptyEWAH((runLen + 1) * uintSize)
+	e.addClean(uintMax, runLen)
+	e.addLiteral(1)
+
+	assert.Equal(t, []uint{1 | ewahMaxRunLen<<1, 1 | 10<<1 | 1<<(1+ewahRunLenBits), 1}, e.words)
+	assert.Equal(t, runLen*uintSize+1, e.Count())
+}
+
+func TestEWAHOps(t *testing.T) {
+	ops := map[string]struct {
+		ewahOp func(e *EWAH, other *EWAH) *EWAH
+		op     func(dst, a, b *BitMask) uint
+	}{
+		"and":    {(*EWAH).And, And},
+		"or":     {(*EWAH).Or, Or},
+		"xor":    {(*EWAH).Xor, Xor},
+		"andnot": {(*EWAH).AndNot, AndNot},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for name, op := range ops {
+		t.Run(name, func(t *testing.T) {
+			for range 100 {
+				a := randomRunsBitMask(rnd, uint(rnd.Intn(30*uintSize)))
+				b := randomRunsBitMask(rnd, uint(rnd.Intn(30*uintSize)))
+
+				// shorter one is padded with cleared bits
+				n := max(a.Len(), b.Len())
+				expected, bPadded := New(n), New(n)
+				Copy(expected, a)
+				Copy(bPadded, b)
+				op.op(expected, expected, bPadded)
+
+				actual := op.ewahOp(NewEWAH(a), NewEWAH(b))
+				assert.True(t, Equal(expected, actual.BitMask()))
+				assert.Equal(t, expected.Count(), actual.Count())
+				// result is compressed as well
+				assert.Equal(t, NewEWAH(expected).words, actual.words)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package bitmask
+
+// Returns the capacity of bitmask in bits: the maximum length it can be resized to without reallocation.
+// Just like for Go slices, capacity of the sliced bitmask includes the rest of the original buffer.
+func (bm *BitMask) Cap() uint {
+	return uint(cap(bm.store))*uintSize - bm.offset
+}
+
+// Changes the length of bitmask.
+// If n <= Cap(), works like s = s[:n] for Go slices: buffer is not reallocated, and the bits between the old and the new length
+// are exposed as they are (for example, Resize(Cap()) on a slice exposes the rest of the original bitmask).
+// Otherwise, new buffer is allocated, so the bitmask will not share it with other slices anymore, and new bits are cleared.
+func (bm *BitMask) Resize(n uint) {
+	if n > bm.Cap() {
+		bm.grow(n)
+	}
+	bm.store = bm.store[:(bm.offset+n+uintSize-1)/uintSize]
+	bm.len = n
+}
+
+// Increases capacity, if necessary, to guarantee space for another n bits, just like slices.Grow.
+// After Grow(n), at least n bits can be appended without reallocation.
+func (bm *BitMask) Grow(n uint) {
+	if bm.Cap()-bm.len < n {
+		bm.grow(bm.len + n)
+	}
+}
+
+// Appends a bit to the end of bitmask, increasing its length by 1.
+// Just like append() for Go slices, it overwrites the bit of original bitmask, if the receiver is a slice with spare capacity.
+func (bm *BitMask) AppendBit(isSet bool) {
+	bm.Resize(bm.len + 1)
+	if isSet {
+		bm.Set(bm.len - 1)
+	} else {
+		bm.Clear(bm.len - 1)
+	}
+}
+
+// Appends all the bits of other bitmask to the end of bitmask, increasing its length by other.Len().
+// It's safe to append overlapping bitmasks (including the receiver itself).
+// Just like append() for Go slices, it overwrites the bits of original bitmask, if the receiver is a slice with spare capacity.
+func (bm *BitMask) AppendMask(other *BitMask) {
+	// in case other is the receiver
+	src := *other
+	oldLen := bm.len
+	bm.Resize(oldLen + src.len)
+	Copy(bm.Slice(oldLen, bm.len), &src)
+}
+
+// reallocates buffer to fit at least minCap bits, at least doubling the capacity to amortize appends
+func (bm *BitMask) grow(minCap uint) {
+	capUints := max((minCap+uintSize-1)/uintSize, 2*uint(cap(bm.store)))
+	grown := &BitMask{store: make([]uint, (bm.len+uintSize-1)/uintSize, capUints), len: bm.len}
+	Copy(grown, bm)
+	*bm = *grown
+}
@@ -0,0 +1,101 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendBit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	bm := &BitMask{}
+	expected := ""
+	for range 5 * uintSize {
+		isSet := rnd.Intn(2) == 0
+		bm.AppendBit(isSet)
+		if isSet {
+			expected += "1"
+		} else {
+			expected += "0"
+		}
+		assert.Equal(t, expected, bm.Text(FormatBits))
+		assert.GreaterOrEqual(t, bm.Cap(), bm.Len())
+	}
+}
+
+func TestAppendMask(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 100 {
+		bm := randomBitMask(rnd, uint(rnd.Intn(3*uintSize)))
+		other := randomBitMask(rnd, uint(rnd.Intn(3*uintSize)))
+		expected := bm.Text(FormatBits) + other.Text(FormatBits)
+
+		bm.AppendMask(other)
+		assert.Equal(t, expected, bm.Text(FormatBits))
+
+		// appending itself
+		expected = bm.Text(FormatBits) + bm.Text(FormatBits)
+		bm.AppendMask(bm)
+		assert.Equal(t, expected, bm.Text(FormatBits))
+	}
+}
+
+func TestCapSlice(t *testing.T) {
+	base := NewFromUint(0b1001, uintMax)
+	assert.Equal(t, uint(2*uintSize), base.Cap())
+
+	s := base.Slice(2, 4)
+	assert.Equal(t, "[2]{01}", s.String())
+	assert.Equal(t, uint(2*uintSize-2), s.Cap())
+
+	// like s[:cap(s)]
+	s.Resize(s.Cap())
+	assert.Equal(t, uint(2*uintSize-2), s.Len())
+	assert.True(t, Equal(base.Slice(2, base.Len()), s))
+
+	// like append within capacity, overwrites the original bitmask
+	s.Resize(2)
+	s.AppendBit(true)
+	assert.True(t, base.IsSet(4))
+	s.AppendMask(New(1))
+	assert.False(t, base.IsSet(5))
+
+	// beyond capacity, reallocates
+	s.Resize(s.Cap() + 1)
+	s.Set(10)
+	assert.False(t, base.IsSet(12))
+	assert.Equal(t, "0110000000100000", s.Text(FormatBits)[:16])
+	// new bits are cleared
+	assert.False(t, s.IsSet(s.Len()-1))
+
+	// like s[i:i], empty slice keeps the rest of the buffer
+	for i := uint(0); i <= base.Len(); i++ {
+		empty := base.Slice(i, i)
+		assert.Equal(t, uint(0), empty.Len())
+		assert.Equal(t, base.Cap()-i, empty.Cap())
+		empty.Resize(empty.Cap())
+		assert.True(t, Equal(base.Slice(i, base.Len()), empty))
+	}
+}
+
+func TestResizeShrinkAndGrow(t *testing.T) {
+	bm := New(10)
+	bm.SetAll()
+	bm.Resize(5)
+	assert.Equal(t, "[5]{11111}", bm.String())
+
+	// within capacity bits are exposed as is
+	bm.Resize(10)
+	assert.Equal(t, "[10]{1111111111}", bm.String())
+
+	bm.Resize(0)
+	assert.Equal(t, "[0]{}", bm.String())
+	assert.Equal(t, uint(uintSize), bm.Cap())
+
+	bm.Grow(uintSize + 1)
+	assert.GreaterOrEqual(t, bm.Cap(), uint(uintSize+1))
+	capBefore := bm.Cap()
+	bm.Grow(10)
+	assert.Equal(t, capBefore, bm.Cap())
+}
@@ -0,0 +1,28 @@
+package bitmask
+
+import (
+	"hash"
+	"hash/maphash"
+)
+
+// Returns a hash of Len() and bits of bitmask, so that a slice and its copy have the same hash.
+// Hashes are only comparable within the same seed, see maphash.
+func (bm *BitMask) Hash(seed maphash.Seed) uint64 {
+	return maphash.Bytes(seed, bm.appendCanonical(nil))
+}
+
+// Writes the canonical encoding of bitmask to h, which is the same as returned by MarshalBinary.
+func (bm *BitMask) WriteHash(h hash.Hash) {
+	h.Write(bm.appendCanonical(nil))
+}
+
+// Returns the canonical form of bitmask, which can be used as a map key: keys are equal if and only if
+// bitmasks have the same length and bits. It's the binary encoding (see MarshalBinary), converted to string.
+func (bm *BitMask) Key() string {
+	return string(bm.appendCanonical(nil))
+}
+
+func (bm *BitMask) appendCanonical(b []byte) []byte {
+	b, _ = bm.AppendBinary(b)
+	return b
+}
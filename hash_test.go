@@ -0,0 +1,55 @@
+package bitmask
+
+import (
+	"crypto/sha256"
+	"hash/maphash"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	seed := maphash.MakeSeed()
+	rnd := rand.New(rand.NewSource(1))
+	for range 100 {
+		bm, _ := randomSlice(rnd, 100, uint(rnd.Intn(2000)))
+		clone := bm.clone()
+
+		assert.Equal(t, bm.Hash(seed), clone.Hash(seed))
+		assert.Equal(t, bm.Key(), clone.Key())
+
+		h1, h2 := sha256.New(), sha256.New()
+		bm.WriteHash(h1)
+		clone.WriteHash(h2)
+		assert.Equal(t, h1.Sum(nil), h2.Sum(nil))
+	}
+}
+
+func TestHashDifferent(t *testing.T) {
+	seed := maphash.MakeSeed()
+	// long bitmasks, which differ after 512 bits, where String() is truncated
+	a, b := New(2000), New(2000)
+	a.Set(1500)
+	assert.Equal(t, a.String(), b.String())
+	assert.NotEqual(t, a.Key(), b.Key())
+	assert.NotEqual(t, a.Hash(seed), b.Hash(seed))
+
+	// same bits, different length
+	assert.NotEqual(t, New(8).Key(), New(9).Key())
+	assert.NotEqual(t, New(8).Hash(seed), New(9).Hash(seed))
+}
+
+func TestKeyMap(t *testing.T) {
+	bm := NewFromUint64s(0xdeadbeef, 1, 2)
+	counts := map[string]int{}
+	counts[bm.Key()]++
+	counts[bm.Slice(0, 192).Key()]++
+	counts[NewFromUint64s(0xdeadbeef, 1, 2).Key()]++
+	counts[bm.Slice(1, 192).Key()]++
+	assert.Equal(t, 2, len(counts))
+	assert.Equal(t, 3, counts[bm.Key()])
+
+	data, _ := bm.MarshalBinary()
+	assert.Equal(t, string(data), bm.Key())
+}
@@ -0,0 +1,124 @@
+package bitmask
+
+import "fmt"
+
+// Allocates unique integer IDs (connection IDs, port numbers, file descriptors, ...), tracking them in a BitMask.
+// Alloc searches for a free ID starting after the last allocated one and wrapping around,
+// so the mask is not rescanned from the beginning each time, and freed IDs are not reused immediately.
+// Use AllocLowest when the lowest free ID is required.
+// Not safe for concurrent use.
+type IDAllocator struct {
+	ids *BitMask
+	// where to start searching for a free ID
+	cursor uint
+	// number of allocated IDs
+	count    uint
+	growable bool
+}
+
+// Creates allocator of IDs in the range [0, size).
+func NewIDAllocator(size uint) *IDAllocator {
+	return &IDAllocator{ids: New(size)}
+}
+
+// Creates allocator of IDs, which starts with the range [0, size), and doubles it when there're no free IDs left.
+func NewGrowingIDAllocator(size uint) *IDAllocator {
+	return &IDAllocator{ids: New(size), growable: true}
+}
+
+// Returns the current size of the range of IDs.
+func (a *IDAllocator) Size() uint {
+	return a.ids.len
+}
+
+// Returns the number of allocated IDs.
+func (a *IDAllocator) InUse() uint {
+	return a.count
+}
+
+// Checks, whether the ID is allocated. Returns false for IDs out of range.
+func (a *IDAllocator) IsAllocated(id uint) bool {
+	return id < a.ids.len && a.ids.IsSet(id)
+}
+
+// Allocates a free ID, searching from the position after the last allocated one.
+// If there're no free IDs, and the allocator isn't growing, ok will be false.
+func (a *IDAllocator) Alloc() (id uint, ok bool) {
+	id, ok = a.ids.NextClear(a.cursor)
+	if !ok {
+		id, ok = a.ids.Slice(0, minUint(a.cursor, a.ids.len)).NextClear(0)
+	}
+	if !ok {
+		if !a.growable {
+			return 0, false
+		}
+		id = a.ids.len
+		a.grow(id + 1)
+	}
+	a.take(id)
+	return id, true
+}
+
+// Allocates the lowest free ID. If there're no free IDs, and the allocator isn't growing, ok will be false.
+func (a *IDAllocator) AllocLowest() (id uint, ok bool) {
+	id, ok = a.ids.NextClear(0)
+	if !ok {
+		if !a.growable {
+			return 0, false
+		}
+		id = a.ids.len
+		a.grow(id + 1)
+	}
+	a.take(id)
+	return id, true
+}
+
+// Allocates the specific ID. Returns false if it's already allocated.
+// Panics if the ID is out of range, unless the allocator is growing.
+func (a *IDAllocator) AllocAt(id uint) bool {
+	if id >= a.ids.len && a.growable {
+		a.grow(id + 1)
+	}
+	if a.ids.IsSet(id) {
+		return false
+	}
+	a.take(id)
+	return true
+}
+
+// Allocates n IDs (not necessarily consecutive). Either all of them are allocated, or none (ok will be false).
+func (a *IDAllocator) AllocN(n uint) (ids []uint, ok bool) {
+	if free := a.ids.len - a.count; free < n {
+		if !a.growable {
+			return nil, false
+		}
+		a.grow(a.count + n)
+	}
+	ids = make([]uint, n)
+	for i := range ids {
+		ids[i], _ = a.Alloc()
+	}
+	return ids, true
+}
+
+// Releases the ID, so it can be allocated again. Panics if it's not allocated.
+func (a *IDAllocator) Free(id uint) {
+	if !a.IsAllocated(id) {
+		panic(fmt.Sprintf("id %v is not allocated", id))
+	}
+	a.ids.Clear(id)
+	a.count--
+}
+
+func (a *IDAllocator) take(id uint) {
+	a.ids.Set(id)
+	a.count++
+	a.cursor = id + 1
+}
+
+// grows the range of IDs to at least minSize, at least doubling it
+func (a *IDAllocator) grow(minSize uint) {
+	oldSize := a.ids.len
+	a.ids.Resize(max(minSize, 2*oldSize))
+	a.ids.Slice(oldSize, a.ids.len).ClearAll()
+}
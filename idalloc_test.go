@@ -0,0 +1,116 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDAllocatorRotates(t *testing.T) {
+	a := NewIDAllocator(4)
+
+	for expected := uint(0); expected < 4; expected++ {
+		id, ok := a.Alloc()
+		assert.True(t, ok)
+		assert.Equal(t, expected, id)
+	}
+	_, ok := a.Alloc()
+	assert.False(t, ok)
+
+	a.Free(1)
+	a.Free(3)
+	assert.Equal(t, uint(2), a.InUse())
+
+	// wraps around to the first free one
+	id, ok := a.Alloc()
+	assert.True(t, ok)
+	assert.Equal(t, uint(1), id)
+
+	// continues after the last allocated one
+	id, ok = a.Alloc()
+	assert.True(t, ok)
+	assert.Equal(t, uint(3), id)
+}
+
+func TestIDAllocatorLowest(t *testing.T) {
+	a := NewIDAllocator(10)
+	ids, ok := a.AllocN(5)
+	assert.True(t, ok)
+	assert.Equal(t, []uint{0, 1, 2, 3, 4}, ids)
+
+	a.Free(3)
+	a.Free(1)
+
+	id, _ := a.Alloc()
+	assert.Equal(t, uint(5), id)
+	id, _ = a.AllocLowest()
+	assert.Equal(t, uint(1), id)
+}
+
+func TestIDAllocatorAllocAt(t *testing.T) {
+	a := NewIDAllocator(10)
+	assert.True(t, a.AllocAt(7))
+	assert.False(t, a.AllocAt(7))
+	assert.True(t, a.IsAllocated(7))
+	assert.False(t, a.IsAllocated(100))
+
+	id, _ := a.Alloc()
+	assert.Equal(t, uint(8), id)
+
+	assert.Panics(t, func() { a.AllocAt(10) })
+	assert.Panics(t, func() { a.Free(0) })
+}
+
+func TestIDAllocatorAllocNAllOrNothing(t *testing.T) {
+	a := NewIDAllocator(10)
+	_, ok := a.AllocN(8)
+	assert.True(t, ok)
+
+	ids, ok := a.AllocN(3)
+	assert.False(t, ok)
+	assert.Nil(t, ids)
+	assert.Equal(t, uint(8), a.InUse())
+}
+
+func TestIDAllocatorGrowing(t *testing.T) {
+	a := NewGrowingIDAllocator(0)
+	for expected := uint(0); expected < 100; expected++ {
+		id, ok := a.Alloc()
+		assert.True(t, ok)
+		assert.Equal(t, expected, id)
+	}
+	assert.GreaterOrEqual(t, a.Size(), uint(100))
+
+	size := a.Size()
+	ids, ok := a.AllocN(size)
+	assert.True(t, ok)
+	assert.Len(t, ids, int(size))
+	assert.Equal(t, 100+size, a.InUse())
+
+	assert.True(t, a.AllocAt(10000))
+	assert.True(t, a.IsAllocated(10000))
+}
+
+func TestIDAllocatorRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	a := NewIDAllocator(300)
+	allocated := map[uint]bool{}
+	for range 10000 {
+		if rnd.Intn(2) == 0 {
+			id, ok := a.Alloc()
+			assert.Equal(t, len(allocated) < 300, ok)
+			if ok {
+				assert.False(t, allocated[id])
+				allocated[id] = true
+			}
+		} else if len(allocated) > 0 {
+			for id := range allocated {
+				a.Free(id)
+				delete(allocated, id)
+				break
+			}
+		}
+		assert.Equal(t, uint(len(allocated)), a.InUse())
+	}
+}
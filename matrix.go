@@ -0,0 +1,168 @@
+package bitmask
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Two-dimensional matrix of bits, stored row by row in a single bitmask, so that bit (r, c) has index r*Cols()+c.
+type BitMatrix struct {
+	bits *BitMask
+	rows uint
+	cols uint
+}
+
+// Creates new zeroed matrix of the given size.
+func NewBitMatrix(rows uint, cols uint) *BitMatrix {
+	return &BitMatrix{bits: New(rows * cols), rows: rows, cols: cols}
+}
+
+// Creates new matrix of the given number of columns, which uses bits of bm (bit (r, c) is bm bit r*cols+c) without copying.
+// Length of bm must be a multiple of cols.
+func NewBitMatrixFromBitMask(bm *BitMask, cols uint) *BitMatrix {
+	if cols == 0 || bm.len%cols != 0 {
+		panic(fmt.Sprintf("length %v is not a multiple of the number of columns %v", bm.len, cols))
+	}
+	return &BitMatrix{bits: bm, rows: bm.len / cols, cols: cols}
+}
+
+// Returns the number of rows.
+func (m *BitMatrix) Rows() uint {
+	return m.rows
+}
+
+// Returns the number of columns.
+func (m *BitMatrix) Cols() uint {
+	return m.cols
+}
+
+// Returns underlying bitmask, where bit (r, c) has index r*Cols()+c.
+func (m *BitMatrix) BitMask() *BitMask {
+	return m.bits
+}
+
+// Returns true if bit (r, c) is set.
+func (m *BitMatrix) Get(r uint, c uint) bool {
+	return m.bits.IsSet(m.index(r, c))
+}
+
+// Sets bit (r, c).
+func (m *BitMatrix) Set(r uint, c uint) {
+	m.bits.Set(m.index(r, c))
+}
+
+// Clears bit (r, c).
+func (m *BitMatrix) Clear(r uint, c uint) {
+	m.bits.Clear(m.index(r, c))
+}
+
+// Returns a bitmask of Cols() bits, sharing the memory with the row r of the matrix (see Slice).
+// It can be used for row-wise operations, e.g. m.Row(i).Or(m.Row(j)).
+func (m *BitMatrix) Row(r uint) *BitMask {
+	checkBounds(m.rows, r)
+	return m.bits.Slice(r*m.cols, (r+1)*m.cols)
+}
+
+// Returns a new bitmask of Rows() bits with the copy of column c.
+func (m *BitMatrix) Column(c uint) *BitMask {
+	checkBounds(m.cols, c)
+	column := New(m.rows)
+	for r := uint(0); r < m.rows; r++ {
+		if m.bits.IsSet(r*m.cols + c) {
+			column.Set(r)
+		}
+	}
+	return column
+}
+
+// Returns new transposed matrix, so that bit (r, c) of the result equals to bit (c, r) of m.
+// Matrix is processed by blocks of uintSize x uintSize bits.
+func (m *BitMatrix) Transpose() *BitMatrix {
+	result := NewBitMatrix(m.cols, m.rows)
+	var block [uintSize]uint
+	for blockRow := uint(0); blockRow < m.rows; blockRow += uintSize {
+		height := minUint(uintSize, m.rows-blockRow)
+		for blockCol := uint(0); blockCol < m.cols; blockCol += uintSize {
+			width := minUint(uintSize, m.cols-blockCol)
+			// loadWord may also return the bits of the next row, which are cut off
+			widthMask := ^(uintMax >> width)
+			for i := uint(0); i < uintSize; i++ {
+				block[i] = 0
+				if i < height {
+					block[i] = m.bits.loadWord((blockRow+i)*m.cols+blockCol) & widthMask
+				}
+			}
+			transposeBlock(&block)
+			for i := uint(0); i < width; i++ {
+				result.bits.storeWord((blockCol+i)*m.rows+blockRow, height, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// Keeps only those bits, which are also set in other. Panics if matrices have different sizes.
+func (m *BitMatrix) And(other *BitMatrix) {
+	m.checkSize(other)
+	m.bits.And(other.bits)
+}
+
+// Sets bits, which are set in other. Panics if matrices have different sizes.
+func (m *BitMatrix) Or(other *BitMatrix) {
+	m.checkSize(other)
+	m.bits.Or(other.bits)
+}
+
+// Toggles bits, which are set in other. Panics if matrices have different sizes.
+func (m *BitMatrix) Xor(other *BitMatrix) {
+	m.checkSize(other)
+	m.bits.Xor(other.bits)
+}
+
+// Clears bits, which are set in other. Panics if matrices have different sizes.
+func (m *BitMatrix) AndNot(other *BitMatrix) {
+	m.checkSize(other)
+	m.bits.AndNot(other.bits)
+}
+
+// Returns string representation of the matrix, rows are separated by new lines.
+func (m *BitMatrix) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("[%vx%v]", m.rows, m.cols))
+	for r := uint(0); r < m.rows; r++ {
+		sb.WriteByte('\n')
+		for c := uint(0); c < m.cols; c++ {
+			if m.bits.IsSet(r*m.cols + c) {
+				sb.WriteByte('1')
+			} else {
+				sb.WriteByte('0')
+			}
+		}
+	}
+	return sb.String()
+}
+
+func (m *BitMatrix) index(r uint, c uint) uint {
+	checkBounds(m.rows, r)
+	checkBounds(m.cols, c)
+	return r*m.cols + c
+}
+
+func (m *BitMatrix) checkSize(other *BitMatrix) {
+	if m.rows != other.rows || m.cols != other.cols {
+		panic(fmt.Sprintf("matrix size mismatch: %vx%v and %vx%v", m.rows, m.cols, other.rows, other.cols))
+	}
+}
+
+// transposes uintSize x uintSize block, where bit j of the row i is the (j+1)-th most significant bit of block[i].
+// Recursively swaps off-diagonal quadrants, see Hacker's Delight, 7-3 "Transposing a Bit Matrix".
+func transposeBlock(block *[uintSize]uint) {
+	mask := uintMax >> (uintSize / 2)
+	for j := uint(uintSize / 2); j != 0; j, mask = j>>1, mask^(mask<<(j>>1)) {
+		for k := uint(0); k < uintSize; k = (k + j + 1) &^ j {
+			t := (block[k] ^ (block[k+j] >> j)) & mask
+			block[k] ^= t
+			block[k+j] ^= t << j
+		}
+	}
+}
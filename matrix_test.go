@@ -0,0 +1,112 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomBitMatrix(rnd *rand.Rand, rows, cols uint) *BitMatrix {
+	return NewBitMatrixFromBitMask(randomBitMask(rnd, rows*cols), max(cols, 1))
+}
+
+func TestBitMatrix(t *testing.T) {
+	m := NewBitMatrix(3, 4)
+	assert.Equal(t, uint(3), m.Rows())
+	assert.Equal(t, uint(4), m.Cols())
+
+	m.Set(0, 1)
+	m.Set(1, 3)
+	m.Set(2, 0)
+	m.Set(2, 2)
+	m.Clear(2, 2)
+	assert.True(t, m.Get(1, 3))
+	assert.False(t, m.Get(2, 2))
+	assert.Equal(t, "[3x4]\n0100\n0001\n1000", m.String())
+	assert.Equal(t, "[12]{010000011000}", m.BitMask().String())
+
+	assert.Panics(t, func() { m.Get(3, 0) })
+	assert.Panics(t, func() { m.Set(0, 4) })
+	assert.Panics(t, func() { m.Row(3) })
+	assert.Panics(t, func() { m.Column(4) })
+	assert.Panics(t, func() { NewBitMatrixFromBitMask(New(10), 4) })
+}
+
+func TestBitMatrixRowView(t *testing.T) {
+	m := NewBitMatrix(3, 70)
+	row := m.Row(1)
+	assert.Equal(t, uint(70), row.Len())
+
+	row.Set(69)
+	assert.True(t, m.Get(1, 69))
+	m.Set(1, 0)
+	assert.True(t, row.IsSet(0))
+
+	// row-wise operation through views
+	m.Row(2).Or(m.Row(1))
+	assert.True(t, m.Get(2, 0))
+	assert.True(t, m.Get(2, 69))
+	assert.Equal(t, uint(4), m.BitMask().Count())
+}
+
+func TestBitMatrixColumn(t *testing.T) {
+	m := NewBitMatrix(3, 4)
+	m.Set(0, 2)
+	m.Set(2, 2)
+	m.Set(1, 1)
+	assert.Equal(t, "[3]{101}", m.Column(2).String())
+	assert.Equal(t, "[3]{010}", m.Column(1).String())
+
+	// column is a copy
+	m.Column(2).ClearAll()
+	assert.True(t, m.Get(0, 2))
+}
+
+func TestBitMatrixTranspose(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sizes := [][2]uint{{0, 0}, {1, 1}, {3, 5}, {64, 64}, {63, 65}, {65, 63}, {128, 1}, {1, 130}, {100, 200}, {129, 191}}
+	for _, size := range sizes {
+		m := randomBitMatrix(rnd, size[0], size[1])
+		tr := m.Transpose()
+		assert.Equal(t, m.Cols(), tr.Rows())
+		assert.Equal(t, m.Rows(), tr.Cols())
+		for r := uint(0); r < m.Rows(); r++ {
+			for c := uint(0); c < m.Cols(); c++ {
+				if m.Get(r, c) != tr.Get(c, r) {
+					t.Fatalf("%v: bit (%v, %v) mismatch", size, r, c)
+				}
+			}
+		}
+		if m.Rows() > 0 && m.Cols() > 0 {
+			assert.Equal(t, m.String(), tr.Transpose().String())
+		}
+	}
+}
+
+func TestBitMatrixTransposeSliced(t *testing.T) {
+	// matrix over a sliced bitmask with non-zero offset
+	base := randomBitMask(rand.New(rand.NewSource(2)), 1000)
+	m := NewBitMatrixFromBitMask(base.Slice(7, 7+70*9), 70)
+	tr := m.Transpose()
+	for c := uint(0); c < m.Cols(); c++ {
+		assert.Equal(t, bitString(m.Column(c)), bitString(tr.Row(c)))
+	}
+}
+
+func TestBitMatrixOps(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	a, b := randomBitMatrix(rnd, 5, 70), randomBitMatrix(rnd, 5, 70)
+
+	expected := a.BitMask().clone()
+	expected.Xor(b.BitMask())
+	a.Xor(b)
+	assert.Equal(t, expected.String(), a.BitMask().String())
+
+	a.Or(b)
+	a.AndNot(b)
+	a.And(b)
+	assert.True(t, a.BitMask().None())
+
+	assert.Panics(t, func() { a.Or(NewBitMatrix(70, 5)) })
+}
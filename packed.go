@@ -0,0 +1,113 @@
+package bitmask
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+)
+
+// Returns width (up to 64) bits starting from the index from as an integer, where bit from+j is the bit j of the value
+// (the same order as in NewFromUint).
+func (bm *BitMask) GetBits(from uint, width uint) uint64 {
+	checkFieldBounds(bm.len, from, width)
+	if width == 0 {
+		return 0
+	}
+	return bits.Reverse64(bm.loadStream(from, width))
+}
+
+// Writes width (up to 64) least significant bits of value starting from the index from, so that bit from+j is the bit j of the value.
+// Other bits of value are ignored.
+func (bm *BitMask) PutBits(from uint, width uint, value uint64) {
+	checkFieldBounds(bm.len, from, width)
+	bm.storeStream(from, width, bits.Reverse64(value))
+}
+
+// Array of fixed-width unsigned integers, packed into a BitMask, so that element i occupies bits [i*width, (i+1)*width)
+// (see GetBits for the order of bits).
+type PackedArray struct {
+	bits  *BitMask
+	width uint
+}
+
+// Creates new array of n zero elements, each of width bits (1 to 64).
+func NewPackedArray(n uint, width uint) *PackedArray {
+	if width == 0 || width > 64 {
+		panic(fmt.Sprintf("width %v is out of range [1, 64]", width))
+	}
+	return &PackedArray{bits: New(n * width), width: width}
+}
+
+// Returns the number of elements.
+func (a *PackedArray) Len() uint {
+	return a.bits.len / a.width
+}
+
+// Returns the width of elements in bits.
+func (a *PackedArray) Width() uint {
+	return a.width
+}
+
+// Returns underlying bitmask.
+func (a *PackedArray) BitMask() *BitMask {
+	return a.bits
+}
+
+// Returns element i.
+func (a *PackedArray) Get(i uint) uint64 {
+	checkBounds(a.Len(), i)
+	return a.bits.GetBits(i*a.width, a.width)
+}
+
+// Sets element i to value. Panics if the value doesn't fit into Width() bits.
+func (a *PackedArray) Set(i uint, value uint64) {
+	checkBounds(a.Len(), i)
+	if uint(bits.Len64(value)) > a.width {
+		panic(fmt.Sprintf("value %v doesn't fit into %v bits", value, a.width))
+	}
+	a.bits.PutBits(i*a.width, a.width, value)
+}
+
+// Iterates through the elements with their indexes.
+func (a *PackedArray) Values() iter.Seq2[uint, uint64] {
+	return func(yield func(uint, uint64) bool) {
+		n := a.Len()
+		for i := uint(0); i < n; i++ {
+			if !yield(i, a.bits.GetBits(i*a.width, a.width)) {
+				return
+			}
+		}
+	}
+}
+
+// returns n (up to 64) bits starting from bitIndex as the most significant bits of the result
+func (bm *BitMask) loadStream(bitIndex uint, n uint) uint64 {
+	var stream uint64
+	for read := uint(0); read < n; {
+		chunk := minUint(uintSize, n-read)
+		w := bm.loadWord(bitIndex+read) & ^(uintMax >> chunk)
+		stream |= uint64(w) << (64 - uintSize) >> read
+		read += chunk
+	}
+	return stream
+}
+
+// writes n (up to 64) most significant bits of stream starting from bitIndex
+func (bm *BitMask) storeStream(bitIndex uint, n uint, stream uint64) {
+	for n > 0 {
+		chunk := minUint(uintSize, n)
+		bm.storeWord(bitIndex, chunk, uint(stream>>(64-uintSize)))
+		stream <<= chunk
+		bitIndex += chunk
+		n -= chunk
+	}
+}
+
+func checkFieldBounds(len uint, from uint, width uint) {
+	if width > 64 {
+		panic(fmt.Sprintf("width %v is too big, maximum is 64", width))
+	}
+	if from > len || width > len-from {
+		panic(fmt.Sprintf("bits [%v:%v] out of range with length %v", from, from+width, len))
+	}
+}
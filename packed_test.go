@@ -0,0 +1,107 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPutBits(t *testing.T) {
+	bm := New(200)
+	bm.PutBits(60, 7, 0b1010011)
+	bm.PutBits(3, 3, 0b1111) // extra bits are ignored
+	assert.Equal(t, uint64(0b1010011), bm.GetBits(60, 7))
+	assert.Equal(t, uint64(0b111), bm.GetBits(3, 3))
+	assert.Equal(t, uint64(0b10100110), bm.GetBits(59, 8))
+	assert.True(t, bm.IsSet(60))
+	assert.False(t, bm.IsSet(62))
+	assert.True(t, bm.IsSet(66))
+	assert.Equal(t, uint64(0), bm.GetBits(60, 0))
+
+	bm.PutBits(100, 64, ^uint64(0))
+	assert.Equal(t, ^uint64(0), bm.GetBits(100, 64))
+	assert.Equal(t, uint(64+3+4), bm.Count())
+
+	assert.Panics(t, func() { bm.GetBits(190, 11) })
+	assert.Panics(t, func() { bm.PutBits(0, 65, 0) })
+	assert.Panics(t, func() { bm.GetBits(201, 0) })
+	assert.NotPanics(t, func() { bm.GetBits(200, 0) })
+}
+
+func TestGetPutBitsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 500 {
+		bm, base := randomSlice(rnd, 100, uint(rnd.Intn(200)))
+		width := uint(rnd.Intn(min(65, int(bm.Len())+1)))
+		at := uint(rnd.Intn(int(bm.Len()-width) + 1))
+
+		expected := uint64(0)
+		for j := range width {
+			if bm.IsSet(at + j) {
+				expected |= 1 << j
+			}
+		}
+		assert.Equal(t, expected, bm.GetBits(at, width))
+
+		value := rnd.Uint64()
+		src := bm.clone()
+		expectedBase := base.clone()
+		expectedBits := bm.clone()
+		for j := range width {
+			if value&(1<<j) != 0 {
+				expectedBits.Set(at + j)
+			} else {
+				expectedBits.Clear(at + j)
+			}
+		}
+		bm.PutBits(at, width, value)
+		assert.Equal(t, bitString(expectedBits), bitString(bm))
+
+		// neighbour bits must stay untouched
+		Copy(bm, src)
+		assert.Equal(t, bitString(expectedBase), bitString(base))
+	}
+}
+
+func TestPackedArray(t *testing.T) {
+	a := NewPackedArray(1000, 11)
+	assert.Equal(t, uint(1000), a.Len())
+	assert.Equal(t, uint(11), a.Width())
+	assert.Equal(t, uint(11000), a.BitMask().Len())
+
+	rnd := rand.New(rand.NewSource(1))
+	expected := make([]uint64, 1000)
+	for i := range expected {
+		expected[i] = uint64(rnd.Intn(1 << 11))
+		a.Set(uint(i), expected[i])
+	}
+	for i, v := range expected {
+		assert.Equal(t, v, a.Get(uint(i)))
+	}
+
+	n := uint(0)
+	for i, v := range a.Values() {
+		assert.Equal(t, n, i)
+		assert.Equal(t, expected[i], v)
+		n++
+		if n == 500 {
+			break
+		}
+	}
+	assert.Equal(t, uint(500), n)
+
+	assert.Panics(t, func() { a.Set(0, 1<<11) })
+	assert.Panics(t, func() { a.Set(1000, 0) })
+	assert.Panics(t, func() { a.Get(1000) })
+	assert.Panics(t, func() { NewPackedArray(1, 0) })
+	assert.Panics(t, func() { NewPackedArray(1, 65) })
+}
+
+func TestPackedArrayFullWidth(t *testing.T) {
+	a := NewPackedArray(3, 64)
+	a.Set(1, ^uint64(0))
+	assert.Equal(t, uint64(0), a.Get(0))
+	assert.Equal(t, ^uint64(0), a.Get(1))
+	assert.Equal(t, uint64(0), a.Get(2))
+}
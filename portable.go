@@ -0,0 +1,69 @@
+package bitmask
+
+import "iter"
+
+// Creates a bitmask from uint64 values, so that bit i is the bit i%64 of values[i/64] (NewFromUint64s(1) has the bit 0 set).
+// Unlike NewFromUint, the result doesn't depend on the platform. Len() of the resulting bitmask is 64*len(values).
+func NewFromUint64s(values ...uint64) *BitMask {
+	bm := New(64 * uint(len(values)))
+	for i, v := range values {
+		bm.PutBits(64*uint(i), 64, v)
+	}
+	return bm
+}
+
+// Creates a bitmask from uint32 values, so that bit i is the bit i%32 of values[i/32] (NewFromUint32s(1) has the bit 0 set).
+// Unlike NewFromUint, the result doesn't depend on the platform. Len() of the resulting bitmask is 32*len(values).
+func NewFromUint32s(values ...uint32) *BitMask {
+	bm := New(32 * uint(len(values)))
+	for i, v := range values {
+		bm.PutBits(32*uint(i), 32, uint64(v))
+	}
+	return bm
+}
+
+// Returns the length of bitmask in uint64 values, rounded up.
+func (bm *BitMask) LenUint64() int {
+	return int((bm.len + 63) / 64)
+}
+
+// Returns the length of bitmask in uint32 values, rounded up.
+func (bm *BitMask) LenUint32() int {
+	return int((bm.len + 31) / 32)
+}
+
+// Returns bits [64*index, 64*index+64) as uint64, so that bit 64*index+j is the bit j of the result.
+// Bits after Len() are zero. Unlike Uint, the result doesn't depend on the platform, and the slice offset is taken into account.
+func (bm *BitMask) Uint64(index int) uint64 {
+	checkBounds(uint(bm.LenUint64()), uint(index))
+	from := 64 * uint(index)
+	return bm.GetBits(from, minUint(64, bm.len-from))
+}
+
+// Returns bits [32*index, 32*index+32) as uint32, so that bit 32*index+j is the bit j of the result.
+// Bits after Len() are zero. Unlike Uint, the result doesn't depend on the platform, and the slice offset is taken into account.
+func (bm *BitMask) Uint32(index int) uint32 {
+	checkBounds(uint(bm.LenUint32()), uint(index))
+	from := 32 * uint(index)
+	return uint32(bm.GetBits(from, minUint(32, bm.len-from)))
+}
+
+// Appends LenUint64() values, returned by Uint64, to dst and returns the extended slice.
+// The result can be passed to NewFromUint64s to restore the bitmask (rounded up to 64 bits) on any platform.
+func (bm *BitMask) AppendUint64s(dst []uint64) []uint64 {
+	for i := 0; i < bm.LenUint64(); i++ {
+		dst = append(dst, bm.Uint64(i))
+	}
+	return dst
+}
+
+// Go >=1.23 iterator over LenUint64() values, returned by Uint64. Unlike Words, doesn't depend on the platform.
+func (bm *BitMask) Uint64s() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for i := 0; i < bm.LenUint64(); i++ {
+			if !yield(bm.Uint64(i)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package bitmask
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// These tests don't depend on the size of uint, and are expected to pass with any GOARCH, e.g. GOARCH=386 go test ./...
+
+func TestNewFromUint64s(t *testing.T) {
+	bm := NewFromUint64s(1, 1<<63|0b110)
+	assert.Equal(t, uint(128), bm.Len())
+	assert.Equal(t, []uint{0, 65, 66, 127}, sortedSetBits(bm))
+	assert.Equal(t, 2, bm.LenUint64())
+	assert.Equal(t, 4, bm.LenUint32())
+	assert.Equal(t, uint64(1<<63|0b110), bm.Uint64(1))
+	assert.Equal(t, uint32(0b110), bm.Uint32(2))
+	assert.Equal(t, uint32(1<<31), bm.Uint32(3))
+	assert.Equal(t, []uint64{42, 1, 1<<63 | 0b110}, bm.AppendUint64s([]uint64{42}))
+
+	assert.Panics(t, func() { bm.Uint64(2) })
+	assert.Panics(t, func() { bm.Uint32(4) })
+	assert.Equal(t, uint(0), NewFromUint64s().Len())
+}
+
+func TestNewFromUint32s(t *testing.T) {
+	bm := NewFromUint32s(1<<31, 0b101, 1)
+	assert.Equal(t, uint(96), bm.Len())
+	assert.Equal(t, []uint{31, 32, 34, 64}, sortedSetBits(bm))
+	assert.Equal(t, uint64(0b101<<32|1<<31), bm.Uint64(0))
+	assert.Equal(t, uint64(1), bm.Uint64(1))
+	assert.Equal(t, []uint64{0b101<<32 | 1<<31, 1}, bm.AppendUint64s(nil))
+}
+
+func TestUint64Sliced(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	values := []uint64{rnd.Uint64(), rnd.Uint64(), rnd.Uint64()}
+	bm := NewFromUint64s(values...)
+
+	sliced := bm.Slice(3, 3+64+10)
+	assert.Equal(t, 2, sliced.LenUint64())
+	assert.Equal(t, values[0]>>3|values[1]<<61, sliced.Uint64(0))
+	assert.Equal(t, values[1]>>3&(1<<10-1), sliced.Uint64(1))
+	assert.Equal(t, uint32(values[1]>>3)&(1<<10-1), sliced.Uint32(2))
+
+	assert.Equal(t, sliced.AppendUint64s(nil), slices.Collect(sliced.Uint64s()))
+
+	restored := NewFromUint64s(sliced.AppendUint64s(nil)...)
+	assert.Equal(t, bitString(sliced), bitString(restored.Slice(0, sliced.Len())))
+	assert.True(t, restored.Slice(sliced.Len(), restored.Len()).None())
+}
+
+// golden values, which must be the same on every platform
+func TestPortableEncoding(t *testing.T) {
+	bm := NewFromUint64s(0x8000000000000001, 0x00000000deadbeef).Slice(0, 100)
+
+	binary, err := bm.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, "01640100000000000080efbeadde00", hex.EncodeToString(binary))
+
+	text, err := bm.MarshalText()
+	assert.NoError(t, err)
+	decoded := &BitMask{}
+	assert.NoError(t, decoded.UnmarshalText(text))
+	assert.Equal(t, []uint64{0x8000000000000001, 0xdeadbeef}, decoded.AppendUint64s(nil))
+
+	assert.Equal(t, "0100000000000080efbeadde00", hex.EncodeToString(bm.Bytes(LSBFirst)))
+	assert.Equal(t, "8000000000000001f77db57b00", hex.EncodeToString(bm.Bytes(MSBFirst)))
+
+	assert.Equal(t, uint(2+24), bm.Count())
+	first, _ := bm.First()
+	last, _ := bm.Last()
+	assert.Equal(t, uint(0), first)
+	assert.Equal(t, uint(64+31), last)
+}
+
+func sortedSetBits(bm *BitMask) []uint {
+	result := []uint{}
+	for i := range bm.SetBits() {
+		result = append(result, i)
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package bitmask
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Strategy of choosing a run of free blocks in RangeAllocator.
+type FitPolicy int
+
+const (
+	// Chooses the first run of free blocks, which is long enough.
+	FirstFit FitPolicy = iota
+	// Chooses the shortest run of free blocks, which is long enough, to keep longer runs for bigger allocations.
+	BestFit
+	// Works like FirstFit, but starts searching after the last allocation, wrapping around.
+	NextFit
+)
+
+// Allocates ranges of contiguous blocks (e.g. disk blocks or pages), tracking them in a BitMask, where set bit means allocated block.
+// Not safe for concurrent use.
+type RangeAllocator struct {
+	blocks *BitMask
+	policy FitPolicy
+	// where NextFit starts searching
+	cursor uint
+	// number of free blocks
+	free uint
+}
+
+// Creates allocator of blocks, tracked by the bitmask. Set bits of the bitmask are considered allocated.
+// Bitmask is used directly, without copying, so it should not be modified by the caller after that.
+func NewRangeAllocator(blocks *BitMask, policy FitPolicy) *RangeAllocator {
+	return &RangeAllocator{blocks: blocks, policy: policy, free: blocks.len - blocks.Count()}
+}
+
+// Allocates n contiguous blocks, returning the index of the first one.
+// If there's no run of free blocks long enough (or n is 0), ok will be false.
+func (a *RangeAllocator) Alloc(n uint) (start uint, ok bool) {
+	if n == 0 || n > a.free {
+		return 0, false
+	}
+	switch a.policy {
+	case BestFit:
+		bestLen := uintMax
+		for runStart, runLen := range a.freeRuns(0) {
+			if runLen >= n && runLen < bestLen {
+				start, bestLen, ok = runStart, runLen, true
+				if runLen == n {
+					break
+				}
+			}
+		}
+	case NextFit:
+		start, ok = a.firstFit(a.cursor, n)
+		if !ok {
+			start, ok = a.firstFit(0, n)
+		}
+	default:
+		start, ok = a.firstFit(0, n)
+	}
+	if !ok {
+		return 0, false
+	}
+
+	a.blocks.Slice(start, start+n).SetAll()
+	a.free -= n
+	a.cursor = start + n
+	return start, true
+}
+
+// Releases n blocks starting from start, so they can be allocated again. Panics if some of them are not allocated.
+func (a *RangeAllocator) Free(start uint, n uint) {
+	blocks := a.blocks.Slice(start, start+n)
+	if !blocks.All() {
+		panic(fmt.Sprintf("blocks [%v:%v] are not allocated", start, start+n))
+	}
+	blocks.ClearAll()
+	a.free += n
+}
+
+// Returns the total number of blocks.
+func (a *RangeAllocator) Len() uint {
+	return a.blocks.len
+}
+
+// Returns the number of free blocks.
+func (a *RangeAllocator) FreeBlocks() uint {
+	return a.free
+}
+
+// Returns the length of the longest run of free blocks, which is the biggest possible allocation.
+func (a *RangeAllocator) LargestFreeRun() uint {
+	largest := uint(0)
+	for _, runLen := range a.freeRuns(0) {
+		largest = max(largest, runLen)
+	}
+	return largest
+}
+
+// Returns the number of runs of free blocks. The bigger it is for the same number of free blocks, the more fragmented they are.
+func (a *RangeAllocator) FreeRuns() uint {
+	count := uint(0)
+	for range a.freeRuns(0) {
+		count++
+	}
+	return count
+}
+
+// returns the first run, which is long enough, starting the search from the specified block
+func (a *RangeAllocator) firstFit(from uint, n uint) (uint, bool) {
+	for runStart, runLen := range a.freeRuns(from) {
+		if runLen >= n {
+			return runStart, true
+		}
+	}
+	return 0, false
+}
+
+// iterates through runs of free blocks (start, length), starting from the specified block
+func (a *RangeAllocator) freeRuns(from uint) iter.Seq2[uint, uint] {
+	return func(yield func(uint, uint) bool) {
+		for start, ok := a.blocks.NextClear(from); ok; start, ok = a.blocks.NextClear(from) {
+			end, ok := a.blocks.NextSet(start)
+			if !ok {
+				end = a.blocks.len
+			}
+			if !yield(start, end-start) {
+				return
+			}
+			from = end
+		}
+	}
+}
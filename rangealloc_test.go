@@ -0,0 +1,104 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeAllocatorPolicies(t *testing.T) {
+	// free runs: [0:3], [5:6], [8:12], [13:15]
+	newBlocks := func() *BitMask {
+		bm := New(16)
+		bm.Set(3)
+		bm.Set(4)
+		bm.Set(6)
+		bm.Set(7)
+		bm.Set(12)
+		bm.Set(15)
+		return bm
+	}
+
+	tests := map[string]struct {
+		policy   FitPolicy
+		sizes    []uint
+		expected []uint
+	}{
+		"first_fit": {FirstFit, []uint{2, 2, 1, 2}, []uint{0, 8, 2, 10}},
+		"best_fit":  {BestFit, []uint{2, 2, 1, 2}, []uint{13, 0, 2, 8}},
+		"next_fit":  {NextFit, []uint{2, 2, 1, 2}, []uint{0, 8, 10, 13}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := NewRangeAllocator(newBlocks(), tc.policy)
+			assert.Equal(t, uint(10), a.FreeBlocks())
+			for i, n := range tc.sizes {
+				start, ok := a.Alloc(n)
+				assert.True(t, ok)
+				assert.Equal(t, tc.expected[i], start)
+			}
+			assert.Equal(t, uint(3), a.FreeBlocks())
+		})
+	}
+}
+
+func TestRangeAllocatorStats(t *testing.T) {
+	a := NewRangeAllocator(New(100), FirstFit)
+	assert.Equal(t, uint(100), a.LargestFreeRun())
+	assert.Equal(t, uint(1), a.FreeRuns())
+
+	for range 10 {
+		a.Alloc(10)
+	}
+	assert.Equal(t, uint(0), a.LargestFreeRun())
+	assert.Equal(t, uint(0), a.FreeRuns())
+	_, ok := a.Alloc(1)
+	assert.False(t, ok)
+
+	a.Free(10, 10)
+	a.Free(50, 10)
+	a.Free(60, 5)
+	assert.Equal(t, uint(15), a.LargestFreeRun())
+	assert.Equal(t, uint(2), a.FreeRuns())
+	assert.Equal(t, uint(25), a.FreeBlocks())
+
+	_, ok = a.Alloc(16)
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { a.Free(5, 10) })
+}
+
+func TestRangeAllocatorRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, policy := range []FitPolicy{FirstFit, BestFit, NextFit} {
+		a := NewRangeAllocator(New(1000), policy)
+		type allocation struct{ start, n uint }
+		allocations := []allocation{}
+		owners := make([]int, 1000)
+
+		for i := range 3000 {
+			if rnd.Intn(3) != 0 {
+				n := uint(1 + rnd.Intn(30))
+				largest := a.LargestFreeRun()
+				start, ok := a.Alloc(n)
+				assert.Equal(t, n <= largest, ok)
+				if ok {
+					for j := start; j < start+n; j++ {
+						assert.Equal(t, 0, owners[j], "block %v is allocated twice", j)
+						owners[j] = i + 1
+					}
+					allocations = append(allocations, allocation{start, n})
+				}
+			} else if len(allocations) > 0 {
+				k := rnd.Intn(len(allocations))
+				al := allocations[k]
+				allocations = append(allocations[:k], allocations[k+1:]...)
+				a.Free(al.start, al.n)
+				for j := al.start; j < al.start+al.n; j++ {
+					owners[j] = 0
+				}
+			}
+		}
+	}
+}
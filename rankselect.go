@@ -0,0 +1,145 @@
+package bitmask
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const rankBlockBits = 512
+const rankSuperblockBits = 1 << 16
+const rankBlockWords = rankBlockBits / uintSize
+const rankBlocksPerSuperblock = rankSuperblockBits / rankBlockBits
+
+// Succinct index over a BitMask, answering rank (number of set bits before the index) in O(1),
+// and select (index of the k-th set or cleared bit) in O(log(n)) time.
+//
+// Set bits are counted for every superblock of 65536 bits (absolute uint64 count)
+// and every block of 512 bits (uint16 count relative to superblock),
+// so the memory overhead is ~3.2% of the bitmask size.
+//
+// The index doesn't copy bits, it refers to the bitmask it was built from.
+// It doesn't track changes, so Rebuild must be called after the bitmask was modified.
+type RankSelect struct {
+	bm *BitMask
+	// number of set bits before each superblock
+	superblocks []uint64
+	// number of set bits before each block, relative to its superblock
+	blocks []uint16
+	// total number of set bits
+	count uint
+}
+
+// Builds rank/select index for the bitmask. Bitmask may be a slice.
+func NewRankSelect(bm *BitMask) *RankSelect {
+	rs := &RankSelect{bm: bm}
+	rs.Rebuild()
+	return rs
+}
+
+// Recalculates the index, should be called after the bitmask was modified.
+func (rs *RankSelect) Rebuild() {
+	storeLen := len(rs.bm.store)
+	if rs.bm.len == 0 {
+		storeLen = 0
+	}
+	blocksN := (storeLen + rankBlockWords - 1) / rankBlockWords
+	superblocksN := (blocksN + rankBlocksPerSuperblock - 1) / rankBlocksPerSuperblock
+
+	rs.superblocks = append(rs.superblocks[:0], make([]uint64, superblocksN)...)
+	rs.blocks = append(rs.blocks[:0], make([]uint16, blocksN)...)
+
+	total := uint64(0)
+	for block := 0; block < blocksN; block++ {
+		superblock := block / rankBlocksPerSuperblock
+		if block%rankBlocksPerSuperblock == 0 {
+			rs.superblocks[superblock] = total
+		}
+		rs.blocks[block] = uint16(total - rs.superblocks[superblock])
+
+		wordsEnd := min(storeLen, (block+1)*rankBlockWords)
+		for i := block * rankBlockWords; i < wordsEnd; i++ {
+			total += uint64(bits.OnesCount(rs.bm.store[i] & rs.bm.getStoreWordMask(i)))
+		}
+	}
+	rs.count = uint(total)
+}
+
+// Returns the number of set bits in the bitmask, as of the last Rebuild.
+func (rs *RankSelect) Count() uint {
+	return rs.count
+}
+
+// Returns the number of set bits before bitIndex (in the range [0, bitIndex)). bitIndex may be equal to Len().
+func (rs *RankSelect) Rank1(bitIndex uint) uint {
+	checkBounds(rs.bm.len+1, bitIndex)
+	if bitIndex == rs.bm.len {
+		return rs.count
+	}
+
+	storeBitIndex := bitIndex + rs.bm.offset
+	wordIndex := int(storeBitIndex / uintSize)
+	block := wordIndex / rankBlockWords
+
+	rank := uint(rs.superblocks[block/rankBlocksPerSuperblock]) + uint(rs.blocks[block])
+	for i := block * rankBlockWords; i < wordIndex; i++ {
+		rank += uint(bits.OnesCount(rs.bm.store[i] & rs.bm.getStoreWordMask(i)))
+	}
+	headMask := ^(uintMax >> (storeBitIndex % uintSize))
+	rank += uint(bits.OnesCount(rs.bm.store[wordIndex] & rs.bm.getStoreWordMask(wordIndex) & headMask))
+	return rank
+}
+
+// Returns the number of cleared bits before bitIndex (in the range [0, bitIndex)). bitIndex may be equal to Len().
+func (rs *RankSelect) Rank0(bitIndex uint) uint {
+	return bitIndex - rs.Rank1(bitIndex)
+}
+
+// Returns the index of the k-th (starting from 0) set bit. If there're not enough set bits, ok will be false.
+func (rs *RankSelect) Select1(k uint) (index uint, ok bool) {
+	if k >= rs.count {
+		return 0, false
+	}
+	return rs.selectBit(k, 0), true
+}
+
+// Returns the index of the k-th (starting from 0) cleared bit. If there're not enough cleared bits, ok will be false.
+func (rs *RankSelect) Select0(k uint) (index uint, ok bool) {
+	if k >= rs.bm.len-rs.count {
+		return 0, false
+	}
+	return rs.selectBit(k, uintMax), true
+}
+
+// returns the number of bits, which are set after being xor-ed with flip word, before the block
+func (rs *RankSelect) blockRank(block int, flip uint) uint {
+	rank := uint(rs.superblocks[block/rankBlocksPerSuperblock]) + uint(rs.blocks[block])
+	if flip == 0 {
+		return rank
+	}
+	blockStart := uint(block) * rankBlockBits
+	if blockStart < rs.bm.offset {
+		// only the first block
+		return 0
+	}
+	return blockStart - rs.bm.offset - rank
+}
+
+func (rs *RankSelect) selectBit(k uint, flip uint) uint {
+	// last block with rank <= k
+	block := sort.Search(len(rs.blocks), func(i int) bool {
+		return rs.blockRank(i, flip) > k
+	}) - 1
+
+	remaining := k - rs.blockRank(block, flip)
+	for i := block * rankBlockWords; ; i++ {
+		w := (rs.bm.store[i] ^ flip) & rs.bm.getStoreWordMask(i)
+		count := uint(bits.OnesCount(w))
+		if remaining < count {
+			for ; remaining > 0; remaining-- {
+				w &^= oneInBE >> bits.LeadingZeros(w)
+			}
+			return uint(i)*uintSize + uint(bits.LeadingZeros(w)) - rs.bm.offset
+		}
+		remaining -= count
+	}
+}
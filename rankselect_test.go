@@ -0,0 +1,76 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankSelect(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []uint{0, 1, uintSize, rankBlockBits, rankBlockBits + 1, rankSuperblockBits, 2*rankSuperblockBits + 77} {
+		for range 3 {
+			bm, _ := randomSlice(rnd, 2*uintSize, n)
+			bm.ClearAll()
+			density := 1 + rnd.Intn(10)
+			for i := uint(0); i < n; i++ {
+				if rnd.Intn(density) == 0 {
+					bm.Set(i)
+				}
+			}
+			rs := NewRankSelect(bm)
+
+			ones, zeros := []uint{}, []uint{}
+			rank := uint(0)
+			for i := uint(0); i < n; i++ {
+				if i%97 == 0 || i+1 == n {
+					assert.Equal(t, rank, rs.Rank1(i))
+					assert.Equal(t, i-rank, rs.Rank0(i))
+				}
+				if bm.IsSet(i) {
+					ones = append(ones, i)
+					rank++
+				} else {
+					zeros = append(zeros, i)
+				}
+			}
+			assert.Equal(t, rank, rs.Rank1(n))
+			assert.Equal(t, rank, rs.Count())
+
+			for k := 0; k < len(ones); k += 1 + rnd.Intn(50) {
+				idx, ok := rs.Select1(uint(k))
+				assert.True(t, ok)
+				assert.Equal(t, ones[k], idx)
+			}
+			for k := 0; k < len(zeros); k += 1 + rnd.Intn(50) {
+				idx, ok := rs.Select0(uint(k))
+				assert.True(t, ok)
+				assert.Equal(t, zeros[k], idx)
+			}
+			_, ok := rs.Select1(uint(len(ones)))
+			assert.False(t, ok)
+			_, ok = rs.Select0(uint(len(zeros)))
+			assert.False(t, ok)
+		}
+	}
+}
+
+func TestRankSelectRebuild(t *testing.T) {
+	bm := New(1000)
+	rs := NewRankSelect(bm)
+	assert.Equal(t, uint(0), rs.Rank1(1000))
+
+	bm.Set(10)
+	bm.Set(700)
+	rs.Rebuild()
+
+	assert.Equal(t, uint(1), rs.Rank1(11))
+	assert.Equal(t, uint(2), rs.Rank1(1000))
+	idx, ok := rs.Select1(1)
+	assert.True(t, ok)
+	assert.Equal(t, uint(700), idx)
+	idx, ok = rs.Select0(10)
+	assert.True(t, ok)
+	assert.Equal(t, uint(11), idx)
+}
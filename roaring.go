@@ -0,0 +1,756 @@
+package bitmask
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+const roaringContainerBits = 1 << 16
+const roaringArrayMaxCount = 4096
+const roaringBitmapBytes = roaringContainerBits / 8
+
+// see https://github.com/RoaringBitmap/RoaringFormatSpec
+const roaringCookieNoRuns = 12346
+const roaringCookieRuns = 12347
+const roaringNoOffsetThreshold = 4
+
+type roaringKind uint8
+
+const (
+	roaringArray roaringKind = iota
+	roaringBitmap
+	roaringRun
+)
+
+type roaringOp uint8
+
+const (
+	roaringAnd roaringOp = iota
+	roaringOr
+	roaringXor
+	roaringAndNot
+)
+
+// Compressed set of uint32 values (or, equivalently, of bit indexes), which is efficient for both sparse and dense data.
+// Values are split into containers by their high 16 bits. Each container is either a sorted array of values (up to 4096 values),
+// a BitMask of 65536 bits, or a list of runs of consecutive values.
+// Containers produced by modifications are arrays or bitmaps, use RunOptimize to convert them to runs where it's beneficial.
+//
+// Binary format is compatible with the portable Roaring format (https://github.com/RoaringBitmap/RoaringFormatSpec),
+// so the data can be exchanged with Roaring libraries in other languages.
+type Roaring struct {
+	// high 16 bits of values, sorted
+	keys       []uint16
+	containers []*roaringContainer
+}
+
+// interval of values [start, last]
+type roaringInterval struct {
+	start uint16
+	last  uint16
+}
+
+type roaringContainer struct {
+	kind roaringKind
+	// sorted values of array container
+	array []uint16
+	// 65536 bits of bitmap container
+	bitmap *BitMask
+	// sorted non-overlapping and non-adjacent runs of run container
+	runs []roaringInterval
+	// number of values in the container
+	count int
+}
+
+// Creates new empty Roaring bitmap.
+func NewRoaring() *Roaring {
+	return &Roaring{}
+}
+
+// Creates Roaring bitmap, which contains indexes of all the set bits of bm.
+// Panics if bm is longer than 2^32 bits.
+func NewRoaringFromBitMask(bm *BitMask) *Roaring {
+	if uint64(bm.len) > 1<<32 {
+		panic(fmt.Sprintf("bitmask length %v exceeds 2^32", bm.len))
+	}
+	r := &Roaring{}
+	for from := uint(0); from < bm.len; from += roaringContainerBits {
+		part := bm.Slice(from, minUint(from+roaringContainerBits, bm.len))
+		if part.None() {
+			continue
+		}
+		bitmap := New(roaringContainerBits)
+		Copy(bitmap, part)
+		r.keys = append(r.keys, uint16(from>>16))
+		r.containers = append(r.containers, newRoaringBitmap(bitmap))
+	}
+	return r
+}
+
+// Creates BitMask of the specified length with bits set for all the values.
+// Panics if there're values >= len.
+func (r *Roaring) BitMask(len uint) *BitMask {
+	bm := New(len)
+	for i, c := range r.containers {
+		base := uint(r.keys[i]) << 16
+		switch c.kind {
+		case roaringBitmap:
+			last, _ := c.bitmap.Last()
+			checkBounds(len, base+last)
+			Copy(bm.Slice(base, base+last+1), c.bitmap)
+		case roaringRun:
+			for _, run := range c.runs {
+				checkBounds(len, base+uint(run.last))
+				bm.Slice(base+uint(run.start), base+uint(run.last)+1).SetAll()
+			}
+		default:
+			for _, v := range c.array {
+				bm.Set(base + uint(v))
+			}
+		}
+	}
+	return bm
+}
+
+// Adds the value to the set. Returns false if it was already there.
+func (r *Roaring) Add(x uint32) bool {
+	key, low := uint16(x>>16), uint16(x)
+	i, found := slices.BinarySearch(r.keys, key)
+	if !found {
+		r.keys = slices.Insert(r.keys, i, key)
+		r.containers = slices.Insert(r.containers, i, &roaringContainer{kind: roaringArray, array: []uint16{low}, count: 1})
+		return true
+	}
+	return r.containers[i].add(low)
+}
+
+// Removes the value from the set. Returns false if it wasn't there.
+func (r *Roaring) Remove(x uint32) bool {
+	key, low := uint16(x>>16), uint16(x)
+	i, found := slices.BinarySearch(r.keys, key)
+	if !found || !r.containers[i].remove(low) {
+		return false
+	}
+	if r.containers[i].count == 0 {
+		r.keys = slices.Delete(r.keys, i, i+1)
+		r.containers = slices.Delete(r.containers, i, i+1)
+	}
+	return true
+}
+
+// Checks, whether the value is in the set.
+func (r *Roaring) Contains(x uint32) bool {
+	i, found := slices.BinarySearch(r.keys, uint16(x>>16))
+	return found && r.containers[i].contains(uint16(x))
+}
+
+// Returns the number of values in the set.
+func (r *Roaring) Count() uint64 {
+	count := uint64(0)
+	for _, c := range r.containers {
+		count += uint64(c.count)
+	}
+	return count
+}
+
+// Returns a deep copy of the set.
+func (r *Roaring) Clone() *Roaring {
+	clone := &Roaring{keys: slices.Clone(r.keys), containers: make([]*roaringContainer, len(r.containers))}
+	for i, c := range r.containers {
+		clone.containers[i] = c.clone()
+	}
+	return clone
+}
+
+// Go >=1.23 iterator through the values of the set, in ascending order.
+func (r *Roaring) SetBits() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range r.containers {
+			base := uint32(r.keys[i]) << 16
+			for v := range c.values() {
+				if !yield(base | uint32(v)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keeps only those values, which are also in other.
+func (r *Roaring) And(other *Roaring) {
+	r.apply(other, roaringAnd)
+}
+
+// Adds all the values of other.
+func (r *Roaring) Or(other *Roaring) {
+	r.apply(other, roaringOr)
+}
+
+// Keeps values which are either in r or in other, but not in both.
+func (r *Roaring) Xor(other *Roaring) {
+	r.apply(other, roaringXor)
+}
+
+// Removes all the values of other.
+func (r *Roaring) AndNot(other *Roaring) {
+	r.apply(other, roaringAndNot)
+}
+
+// Converts containers to runs, where it reduces the size, and the other way around.
+func (r *Roaring) RunOptimize() {
+	for _, c := range r.containers {
+		c.runOptimize()
+	}
+}
+
+// Implements encoding.BinaryMarshaler, using portable Roaring format.
+func (r *Roaring) MarshalBinary() ([]byte, error) {
+	hasRuns := slices.ContainsFunc(r.containers, func(c *roaringContainer) bool { return c.kind == roaringRun })
+	n := len(r.containers)
+
+	var b []byte
+	if hasRuns {
+		b = binary.LittleEndian.AppendUint32(b, roaringCookieRuns|uint32(n-1)<<16)
+		runFlags := make([]byte, (n+7)/8)
+		for i, c := range r.containers {
+			if c.kind == roaringRun {
+				runFlags[i/8] |= 1 << (i % 8)
+			}
+		}
+		b = append(b, runFlags...)
+	} else {
+		b = binary.LittleEndian.AppendUint32(b, roaringCookieNoRuns)
+		b = binary.LittleEndian.AppendUint32(b, uint32(n))
+	}
+
+	for i, c := range r.containers {
+		b = binary.LittleEndian.AppendUint16(b, r.keys[i])
+		b = binary.LittleEndian.AppendUint16(b, uint16(c.count-1))
+	}
+
+	if !hasRuns || n >= roaringNoOffsetThreshold {
+		offset := len(b) + 4*n
+		for _, c := range r.containers {
+			b = binary.LittleEndian.AppendUint32(b, uint32(offset))
+			offset += c.serializedSize()
+		}
+	}
+
+	for _, c := range r.containers {
+		switch c.kind {
+		case roaringBitmap:
+			b = c.bitmap.appendBytes(b, LSBFirst)
+		case roaringRun:
+			b = binary.LittleEndian.AppendUint16(b, uint16(len(c.runs)))
+			for _, run := range c.runs {
+				b = binary.LittleEndian.AppendUint16(b, run.start)
+				b = binary.LittleEndian.AppendUint16(b, run.last-run.start)
+			}
+		default:
+			for _, v := range c.array {
+				b = binary.LittleEndian.AppendUint16(b, v)
+			}
+		}
+	}
+	return b, nil
+}
+
+// Implements encoding.BinaryUnmarshaler, using portable Roaring format.
+func (r *Roaring) UnmarshalBinary(data []byte) error {
+	n, err := r.ReadFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if n != int64(len(data)) {
+		return fmt.Errorf("%w: %v unexpected bytes after roaring bitmap", ErrInvalidEncoding, int64(len(data))-n)
+	}
+	return nil
+}
+
+// Implements io.WriterTo, using portable Roaring format.
+func (r *Roaring) WriteTo(w io.Writer) (int64, error) {
+	data, _ := r.MarshalBinary()
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// Implements io.ReaderFrom, using portable Roaring format. Reads exactly one serialized bitmap and replaces the receiver with it.
+func (r *Roaring) ReadFrom(reader io.Reader) (int64, error) {
+	cr := &countingReader{r: reader}
+	decoded, err := readRoaring(cr)
+	if err != nil {
+		if err == io.EOF && cr.n > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		return cr.n, err
+	}
+	*r = *decoded
+	return cr.n, nil
+}
+
+func readRoaring(r *countingReader) (*Roaring, error) {
+	cookie, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+	var runFlags []byte
+	if cookie&0xFFFF == roaringCookieRuns {
+		n = int(cookie>>16) + 1
+		if runFlags, err = r.readBytes((n + 7) / 8); err != nil {
+			return nil, err
+		}
+	} else if cookie == roaringCookieNoRuns {
+		size, err := r.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		if size > roaringContainerBits {
+			return nil, fmt.Errorf("%w: too many roaring containers: %v", ErrInvalidEncoding, size)
+		}
+		n = int(size)
+	} else {
+		return nil, fmt.Errorf("%w: unknown roaring cookie %v", ErrInvalidEncoding, cookie)
+	}
+
+	header, err := r.readBytes(4 * n)
+	if err != nil {
+		return nil, err
+	}
+	if runFlags == nil || n >= roaringNoOffsetThreshold {
+		// offsets are not needed for sequential reading
+		if _, err := r.readBytes(4 * n); err != nil {
+			return nil, err
+		}
+	}
+
+	decoded := &Roaring{keys: make([]uint16, n), containers: make([]*roaringContainer, n)}
+	for i := 0; i < n; i++ {
+		decoded.keys[i] = binary.LittleEndian.Uint16(header[4*i:])
+		if i > 0 && decoded.keys[i] <= decoded.keys[i-1] {
+			return nil, fmt.Errorf("%w: roaring keys are not sorted", ErrInvalidEncoding)
+		}
+		count := int(binary.LittleEndian.Uint16(header[4*i+2:])) + 1
+
+		var c *roaringContainer
+		if runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0 {
+			c, err = readRoaringRuns(r)
+		} else if count > roaringArrayMaxCount {
+			var data []byte
+			if data, err = r.readBytes(roaringBitmapBytes); err == nil {
+				c = newRoaringBitmap(newFromBytes(data, roaringContainerBits, LSBFirst))
+			}
+		} else {
+			c, err = readRoaringArray(r, count)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c.count != count {
+			return nil, fmt.Errorf("%w: roaring container has %v values, expected %v", ErrInvalidEncoding, c.count, count)
+		}
+		decoded.containers[i] = c
+	}
+	return decoded, nil
+}
+
+func readRoaringArray(r *countingReader, count int) (*roaringContainer, error) {
+	data, err := r.readBytes(2 * count)
+	if err != nil {
+		return nil, err
+	}
+	array := make([]uint16, count)
+	for i := range array {
+		array[i] = binary.LittleEndian.Uint16(data[2*i:])
+		if i > 0 && array[i] <= array[i-1] {
+			return nil, fmt.Errorf("%w: roaring array is not sorted", ErrInvalidEncoding)
+		}
+	}
+	return &roaringContainer{kind: roaringArray, array: array, count: count}, nil
+}
+
+func readRoaringRuns(r *countingReader) (*roaringContainer, error) {
+	data, err := r.readBytes(2)
+	if err != nil {
+		return nil, err
+	}
+	runsN := int(binary.LittleEndian.Uint16(data))
+	if data, err = r.readBytes(4 * runsN); err != nil {
+		return nil, err
+	}
+	c := &roaringContainer{kind: roaringRun, runs: make([]roaringInterval, runsN)}
+	for i := range c.runs {
+		start := binary.LittleEndian.Uint16(data[4*i:])
+		length := binary.LittleEndian.Uint16(data[4*i+2:])
+		if uint32(start)+uint32(length) >= roaringContainerBits || (i > 0 && uint32(start) <= uint32(c.runs[i-1].last)+1) {
+			return nil, fmt.Errorf("%w: invalid roaring run", ErrInvalidEncoding)
+		}
+		c.runs[i] = roaringInterval{start, start + length}
+		c.count += int(length) + 1
+	}
+	return c, nil
+}
+
+func (r *Roaring) apply(other *Roaring, op roaringOp) {
+	keys := make([]uint16, 0, max(len(r.keys), len(other.keys)))
+	containers := make([]*roaringContainer, 0, cap(keys))
+	add := func(key uint16, c *roaringContainer) {
+		if c != nil && c.count > 0 {
+			keys = append(keys, key)
+			containers = append(containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(r.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(r.keys) && r.keys[i] < other.keys[j]):
+			// only in r
+			if op != roaringAnd {
+				add(r.keys[i], r.containers[i])
+			}
+			i++
+		case i == len(r.keys) || other.keys[j] < r.keys[i]:
+			// only in other
+			if op == roaringOr || op == roaringXor {
+				add(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			add(r.keys[i], roaringContainerOp(r.containers[i], other.containers[j], op))
+			i++
+			j++
+		}
+	}
+	r.keys, r.containers = keys, containers
+}
+
+func roaringContainerOp(a *roaringContainer, b *roaringContainer, op roaringOp) *roaringContainer {
+	if a.kind == roaringArray && b.kind == roaringArray {
+		return newRoaringArray(mergeSorted(a.array, b.array, op))
+	}
+	if a.kind == roaringArray && (op == roaringAnd || op == roaringAndNot) {
+		return newRoaringArray(filterSorted(a.array, b, op == roaringAnd))
+	}
+	if b.kind == roaringArray && op == roaringAnd {
+		return newRoaringArray(filterSorted(b.array, a, true))
+	}
+
+	result := a.toBitMask()
+	bBitmap := b.bitmap
+	if b.kind != roaringBitmap {
+		bBitmap = b.toBitMask()
+	}
+	switch op {
+	case roaringAnd:
+		result.And(bBitmap)
+	case roaringOr:
+		result.Or(bBitmap)
+	case roaringXor:
+		result.Xor(bBitmap)
+	default:
+		result.AndNot(bBitmap)
+	}
+	return newRoaringBitmap(result)
+}
+
+// merges two sorted arrays
+func mergeSorted(a []uint16, b []uint16, op roaringOp) []uint16 {
+	result := make([]uint16, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			if op != roaringAnd {
+				result = append(result, a[i])
+			}
+			i++
+		case i == len(a) || b[j] < a[i]:
+			if op == roaringOr || op == roaringXor {
+				result = append(result, b[j])
+			}
+			j++
+		default:
+			if op == roaringAnd || op == roaringOr {
+				result = append(result, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// keeps values which are (or are not) in the container
+func filterSorted(values []uint16, c *roaringContainer, keepContained bool) []uint16 {
+	result := make([]uint16, 0, len(values))
+	for _, v := range values {
+		if c.contains(v) == keepContained {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// creates array or bitmap container, depending on the number of values
+func newRoaringArray(values []uint16) *roaringContainer {
+	c := &roaringContainer{kind: roaringArray, array: values, count: len(values)}
+	if c.count > roaringArrayMaxCount {
+		c.convertTo(roaringBitmap)
+	}
+	return c
+}
+
+// creates bitmap or array container, depending on the number of values
+func newRoaringBitmap(bitmap *BitMask) *roaringContainer {
+	c := &roaringContainer{kind: roaringBitmap, bitmap: bitmap, count: int(bitmap.Count())}
+	if c.count <= roaringArrayMaxCount {
+		c.convertTo(roaringArray)
+	}
+	return c
+}
+
+func (c *roaringContainer) contains(v uint16) bool {
+	switch c.kind {
+	case roaringBitmap:
+		return c.bitmap.IsSet(uint(v))
+	case roaringRun:
+		i, _ := slices.BinarySearchFunc(c.runs, v, func(run roaringInterval, v uint16) int {
+			return int(run.last) - int(v)
+		})
+		return i < len(c.runs) && c.runs[i].start <= v
+	default:
+		_, found := slices.BinarySearch(c.array, v)
+		return found
+	}
+}
+
+func (c *roaringContainer) add(v uint16) bool {
+	if c.kind == roaringRun {
+		if c.contains(v) {
+			return false
+		}
+		c.convertTo(c.denseKind())
+	}
+	if c.kind == roaringBitmap {
+		if c.bitmap.IsSet(uint(v)) {
+			return false
+		}
+		c.bitmap.Set(uint(v))
+		c.count++
+		return true
+	}
+
+	i, found := slices.BinarySearch(c.array, v)
+	if found {
+		return false
+	}
+	c.array = slices.Insert(c.array, i, v)
+	c.count++
+	if c.count > roaringArrayMaxCount {
+		c.convertTo(roaringBitmap)
+	}
+	return true
+}
+
+func (c *roaringContainer) remove(v uint16) bool {
+	if !c.contains(v) {
+		return false
+	}
+	if c.kind == roaringRun {
+		c.convertTo(c.denseKind())
+	}
+	c.count--
+	if c.kind == roaringBitmap {
+		c.bitmap.Clear(uint(v))
+		if c.count <= roaringArrayMaxCount {
+			c.convertTo(roaringArray)
+		}
+		return true
+	}
+	i, _ := slices.BinarySearch(c.array, v)
+	c.array = slices.Delete(c.array, i, i+1)
+	return true
+}
+
+func (c *roaringContainer) values() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		switch c.kind {
+		case roaringBitmap:
+			for v := range c.bitmap.SetBits() {
+				if !yield(uint16(v)) {
+					return
+				}
+			}
+		case roaringRun:
+			for _, run := range c.runs {
+				for v := uint32(run.start); v <= uint32(run.last); v++ {
+					if !yield(uint16(v)) {
+						return
+					}
+				}
+			}
+		default:
+			for _, v := range c.array {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// returns new bitmask of 65536 bits with the values of container
+func (c *roaringContainer) toBitMask() *BitMask {
+	switch c.kind {
+	case roaringBitmap:
+		return c.bitmap.clone()
+	case roaringRun:
+		bm := New(roaringContainerBits)
+		for _, run := range c.runs {
+			bm.Slice(uint(run.start), uint(run.last)+1).SetAll()
+		}
+		return bm
+	default:
+		bm := New(roaringContainerBits)
+		for _, v := range c.array {
+			bm.Set(uint(v))
+		}
+		return bm
+	}
+}
+
+// returns array or bitmap kind, whichever is appropriate for the number of values
+func (c *roaringContainer) denseKind() roaringKind {
+	if c.count > roaringArrayMaxCount {
+		return roaringBitmap
+	}
+	return roaringArray
+}
+
+func (c *roaringContainer) convertTo(kind roaringKind) {
+	if c.kind == kind {
+		return
+	}
+	switch kind {
+	case roaringBitmap:
+		c.bitmap = c.toBitMask()
+	case roaringRun:
+		c.runs = slices.Collect(c.runsOf())
+	default:
+		c.array = make([]uint16, 0, c.count)
+		for v := range c.values() {
+			c.array = append(c.array, v)
+		}
+	}
+	c.kind = kind
+	if kind != roaringArray {
+		c.array = nil
+	}
+	if kind != roaringBitmap {
+		c.bitmap = nil
+	}
+	if kind != roaringRun {
+		c.runs = nil
+	}
+}
+
+// iterates through the runs of consecutive values
+func (c *roaringContainer) runsOf() iter.Seq[roaringInterval] {
+	return func(yield func(roaringInterval) bool) {
+		switch c.kind {
+		case roaringBitmap:
+			for start, ok := c.bitmap.NextSet(0); ok; start, ok = c.bitmap.NextSet(start) {
+				end, ok := c.bitmap.NextClear(start)
+				if !ok {
+					end = roaringContainerBits
+				}
+				if !yield(roaringInterval{uint16(start), uint16(end - 1)}) {
+					return
+				}
+				start = end
+			}
+		case roaringRun:
+			for _, run := range c.runs {
+				if !yield(run) {
+					return
+				}
+			}
+		default:
+			for i := 0; i < len(c.array); {
+				j := i + 1
+				for j < len(c.array) && c.array[j] == c.array[j-1]+1 {
+					j++
+				}
+				if !yield(roaringInterval{c.array[i], c.array[j-1]}) {
+					return
+				}
+				i = j
+			}
+		}
+	}
+}
+
+func (c *roaringContainer) runOptimize() {
+	runsN := 0
+	for range c.runsOf() {
+		runsN++
+	}
+	denseKind := c.denseKind()
+	if roaringSerializedSize(roaringRun, c.count, runsN) < roaringSerializedSize(denseKind, c.count, runsN) {
+		c.convertTo(roaringRun)
+	} else {
+		c.convertTo(denseKind)
+	}
+}
+
+func (c *roaringContainer) serializedSize() int {
+	return roaringSerializedSize(c.kind, c.count, len(c.runs))
+}
+
+func roaringSerializedSize(kind roaringKind, count int, runsN int) int {
+	switch kind {
+	case roaringBitmap:
+		return roaringBitmapBytes
+	case roaringRun:
+		return 2 + 4*runsN
+	default:
+		return 2 * count
+	}
+}
+
+func (c *roaringContainer) clone() *roaringContainer {
+	clone := *c
+	clone.array = slices.Clone(c.array)
+	clone.runs = slices.Clone(c.runs)
+	if c.bitmap != nil {
+		clone.bitmap = c.bitmap.clone()
+	}
+	return &clone
+}
+
+// reader which counts the number of bytes read
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) readBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	read, err := io.ReadFull(cr.r, b)
+	cr.n += int64(read)
+	return b, err
+}
+
+func (cr *countingReader) readUint32() (uint32, error) {
+	b, err := cr.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
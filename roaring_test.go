@@ -0,0 +1,260 @@
+package bitmask
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generates values in a few containers, using all kinds of them
+func randomRoaringValues(rnd *rand.Rand) map[uint32]bool {
+	values := map[uint32]bool{}
+	for key := uint32(0); key < 4; key++ {
+		base := key << 16
+		switch rnd.Intn(4) {
+		case 0:
+			// sparse
+			for range rnd.Intn(100) {
+				values[base|uint32(rnd.Intn(roaringContainerBits))] = true
+			}
+		case 1:
+			// dense
+			for range roaringArrayMaxCount + rnd.Intn(10000) {
+				values[base|uint32(rnd.Intn(roaringContainerBits))] = true
+			}
+		case 2:
+			// runs
+			for range 1 + rnd.Intn(10) {
+				start := rnd.Intn(roaringContainerBits - 5000)
+				for v := start; v < start+rnd.Intn(5000); v++ {
+					values[base|uint32(v)] = true
+				}
+			}
+		}
+	}
+	return values
+}
+
+func newRoaringFromValues(values map[uint32]bool, runOptimize bool) *Roaring {
+	r := NewRoaring()
+	for v := range values {
+		r.Add(v)
+	}
+	if runOptimize {
+		r.RunOptimize()
+	}
+	return r
+}
+
+func sortedValues(values map[uint32]bool) []uint32 {
+	sorted := []uint32{}
+	for v := range values {
+		sorted = append(sorted, v)
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+func assertRoaring(t *testing.T, expected map[uint32]bool, r *Roaring) {
+	assert.Equal(t, uint64(len(expected)), r.Count())
+	assert.Equal(t, sortedValues(expected), append([]uint32{}, slices.Collect(r.SetBits())...))
+}
+
+func TestRoaringAddRemove(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, runOptimize := range []bool{false, true} {
+		values := randomRoaringValues(rnd)
+		r := newRoaringFromValues(values, runOptimize)
+		assertRoaring(t, values, r)
+
+		for range 20000 {
+			v := uint32(rnd.Intn(5 << 16))
+			assert.Equal(t, values[v], r.Contains(v))
+			if rnd.Intn(2) == 0 {
+				assert.Equal(t, !values[v], r.Add(v))
+				values[v] = true
+			} else {
+				assert.Equal(t, values[v], r.Remove(v))
+				delete(values, v)
+			}
+		}
+		assertRoaring(t, values, r)
+	}
+}
+
+func TestRoaringOps(t *testing.T) {
+	ops := map[string]struct {
+		op    func(r *Roaring, other *Roaring)
+		naive func(x, y bool) bool
+	}{
+		"and":    {(*Roaring).And, func(x, y bool) bool { return x && y }},
+		"or":     {(*Roaring).Or, func(x, y bool) bool { return x || y }},
+		"xor":    {(*Roaring).Xor, func(x, y bool) bool { return x != y }},
+		"andnot": {(*Roaring).AndNot, func(x, y bool) bool { return x && !y }},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for name, op := range ops {
+		t.Run(name, func(t *testing.T) {
+			for range 10 {
+				aValues, bValues := randomRoaringValues(rnd), randomRoaringValues(rnd)
+				a := newRoaringFromValues(aValues, rnd.Intn(2) == 0)
+				b := newRoaringFromValues(bValues, rnd.Intn(2) == 0)
+				bCopy := b.Clone()
+
+				expected := map[uint32]bool{}
+				for v := range aValues {
+					if op.naive(true, bValues[v]) {
+						expected[v] = true
+					}
+				}
+				for v := range bValues {
+					if op.naive(aValues[v], true) {
+						expected[v] = true
+					}
+				}
+
+				op.op(a, b)
+				assertRoaring(t, expected, a)
+				// other is not modified
+				assertRoaring(t, bValues, bCopy)
+				assertRoaring(t, bValues, b)
+			}
+		})
+	}
+}
+
+func TestRoaringBitMask(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	values := randomRoaringValues(rnd)
+	r := newRoaringFromValues(values, true)
+
+	n := uint(4 << 16)
+	bm := r.BitMask(n)
+	assert.Equal(t, sortedValues(values), func() []uint32 {
+		result := []uint32{}
+		for idx := range bm.SetBits() {
+			result = append(result, uint32(idx))
+		}
+		return result
+	}())
+
+	fromBitMask := NewRoaringFromBitMask(bm.Slice(0, n))
+	assertRoaring(t, values, fromBitMask)
+
+	// sliced bitmask
+	sliced := NewRoaringFromBitMask(bm.Slice(3, n))
+	assert.Equal(t, r.Count()-uint64(bm.CountRange(0, 3)), sliced.Count())
+
+	assert.Panics(t, func() { r.BitMask(uint(sortedValues(values)[len(values)-1])) })
+}
+
+func TestRoaringSerializationRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 10 {
+		values := randomRoaringValues(rnd)
+		for _, runOptimize := range []bool{false, true} {
+			r := newRoaringFromValues(values, runOptimize)
+
+			var buf bytes.Buffer
+			n, err := r.WriteTo(&buf)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(buf.Len()), n)
+
+			data, err := r.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Equal(t, buf.Bytes(), data)
+
+			decoded := NewRoaring()
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assertRoaring(t, values, decoded)
+		}
+	}
+}
+
+func TestRoaringPortableFormat(t *testing.T) {
+	tests := map[string]struct {
+		data     []byte
+		values   []uint32
+		optimize bool
+	}{
+		"array": {
+			data: []byte{
+				0x3A, 0x30, 0, 0, // cookie without runs
+				1, 0, 0, 0, // number of containers
+				0, 0, 3, 0, // key 0, 4 values
+				16, 0, 0, 0, // offset
+				1, 0, 2, 0, 3, 0, 0xE8, 0x03, // values
+			},
+			values: []uint32{1, 2, 3, 1000},
+		},
+		"runs": {
+			data: []byte{
+				0x3B, 0x30, 1, 0, // cookie with runs, 2 containers
+				0b10,       // only the second one is run container
+				0, 0, 0, 0, // key 0, 1 value
+				1, 0, 10, 0, // key 1, 11 values
+				5, 0, // array
+				2, 0, 10, 0, 9, 0, 100, 0, 0, 0, // 2 runs: [10, 19], [100, 100]
+			},
+			values: []uint32{
+				5,
+				1<<16 + 10, 1<<16 + 11, 1<<16 + 12, 1<<16 + 13, 1<<16 + 14,
+				1<<16 + 15, 1<<16 + 16, 1<<16 + 17, 1<<16 + 18, 1<<16 + 19,
+				1<<16 + 100,
+			},
+			optimize: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewRoaring()
+			assert.NoError(t, r.UnmarshalBinary(tc.data))
+			assert.Equal(t, tc.values, slices.Collect(r.SetBits()))
+
+			r = NewRoaring()
+			for _, v := range tc.values {
+				r.Add(v)
+			}
+			if tc.optimize {
+				r.RunOptimize()
+			}
+			data, err := r.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.data, data)
+		})
+	}
+}
+
+func TestRoaringBitmapContainerFormat(t *testing.T) {
+	r := NewRoaring()
+	for v := uint32(0); v <= 2*roaringArrayMaxCount; v += 2 {
+		r.Add(v)
+	}
+	data, err := r.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, 16+roaringBitmapBytes, len(data))
+	// bit j of each little-endian uint64 word k is the value 64k+j
+	assert.Equal(t, []byte{0b01010101, 0b01010101}, data[16:18])
+	assert.Equal(t, byte(0), data[len(data)-1])
+}
+
+func TestRoaringInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"unknown_cookie":  {1, 2, 3, 4},
+		"truncated":       {0x3A, 0x30, 0, 0, 1, 0, 0, 0, 0, 0},
+		"unsorted_array":  {0x3A, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, 1, 0, 16, 0, 0, 0, 2, 0, 1, 0},
+		"unsorted_keys":   {0x3A, 0x30, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 1, 0},
+		"bad_run":         {0x3B, 0x30, 0, 0, 1, 0, 0, 1, 0, 1, 0, 0xFF, 0xFF, 2, 0},
+		"count_mismatch":  {0x3B, 0x30, 0, 0, 1, 0, 0, 5, 0, 1, 0, 1, 0, 2, 0},
+		"trailing_bytes":  {0x3A, 0x30, 0, 0, 0, 0, 0, 0, 0},
+		"too_many_chunks": {0x3A, 0x30, 0, 0, 0, 0, 2, 0},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, NewRoaring().UnmarshalBinary(data))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package bitmask
+
+import "math/bits"
+
+// Returns the index of the first set bit at or after fromBit.
+// If there're no such bits (or fromBit >= Len()), ok will be false.
+func (bm *BitMask) NextSet(fromBit uint) (index uint, ok bool) {
+	return bm.next(fromBit, 0)
+}
+
+// Returns the index of the first cleared bit at or after fromBit.
+// If there're no such bits (or fromBit >= Len()), ok will be false.
+func (bm *BitMask) NextClear(fromBit uint) (index uint, ok bool) {
+	return bm.next(fromBit, uintMax)
+}
+
+// Returns the index of the last set bit at or before fromBit.
+// If fromBit >= Len(), the search starts from the last bit. If there're no such bits, ok will be false.
+func (bm *BitMask) PrevSet(fromBit uint) (index uint, ok bool) {
+	return bm.prev(fromBit, 0)
+}
+
+// Returns the index of the last cleared bit at or before fromBit.
+// If fromBit >= Len(), the search starts from the last bit. If there're no such bits, ok will be false.
+func (bm *BitMask) PrevClear(fromBit uint) (index uint, ok bool) {
+	return bm.prev(fromBit, uintMax)
+}
+
+// Returns the index of the first set bit. Equivalent of NextSet(0).
+func (bm *BitMask) First() (index uint, ok bool) {
+	return bm.next(0, 0)
+}
+
+// Returns the index of the last set bit. Equivalent of PrevSet(Len() - 1).
+func (bm *BitMask) Last() (index uint, ok bool) {
+	return bm.prev(uintMax, 0)
+}
+
+// searches forward for the first bit which is set after being xor-ed with the flip word
+func (bm *BitMask) next(fromBit uint, flip uint) (uint, bool) {
+	if fromBit >= bm.len {
+		return 0, false
+	}
+	storeIndex := int(bm.getStoreIndex(fromBit))
+	w := (bm.store[storeIndex] ^ flip) & bm.getStoreWordMask(storeIndex) & (uintMax >> bm.getBitOffset(fromBit))
+	for {
+		if w != 0 {
+			return uint(storeIndex)*uintSize + uint(bits.LeadingZeros(w)) - bm.offset, true
+		}
+		storeIndex++
+		if storeIndex == len(bm.store) {
+			return 0, false
+		}
+		w = (bm.store[storeIndex] ^ flip) & bm.getStoreWordMask(storeIndex)
+	}
+}
+
+// searches backward for the first bit which is set after being xor-ed with the flip word
+func (bm *BitMask) prev(fromBit uint, flip uint) (uint, bool) {
+	if bm.len == 0 {
+		return 0, false
+	}
+	if fromBit >= bm.len {
+		fromBit = bm.len - 1
+	}
+	storeIndex := int(bm.getStoreIndex(fromBit))
+	w := (bm.store[storeIndex] ^ flip) & bm.getStoreWordMask(storeIndex) & (uintMax << (uintSize - 1 - bm.getBitOffset(fromBit)))
+	for {
+		if w != 0 {
+			return uint(storeIndex)*uintSize + uintSize - 1 - uint(bits.TrailingZeros(w)) - bm.offset, true
+		}
+		if storeIndex == 0 {
+			return 0, false
+		}
+		storeIndex--
+		w = (bm.store[storeIndex] ^ flip) & bm.getStoreWordMask(storeIndex)
+	}
+}
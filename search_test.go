@@ -0,0 +1,101 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func naiveNext(bm *BitMask, fromBit uint, value bool) (uint, bool) {
+	for i := fromBit; i < bm.Len(); i++ {
+		if bm.IsSet(i) == value {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func naivePrev(bm *BitMask, fromBit uint, value bool) (uint, bool) {
+	if bm.Len() == 0 {
+		return 0, false
+	}
+	fromBit = minUint(fromBit, bm.Len()-1)
+	for i := int(fromBit); i >= 0; i-- {
+		if bm.IsSet(uint(i)) == value {
+			return uint(i), true
+		}
+	}
+	return 0, false
+}
+
+func TestSearch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 300 {
+		n := uint(rnd.Intn(5 * uintSize))
+		bm, _ := randomSlice(rnd, 2*uintSize, n)
+		bm.ClearAll()
+		// sparse or dense
+		density := 1 + rnd.Intn(200)
+		for i := uint(0); i < n; i++ {
+			if rnd.Intn(density) == 0 {
+				bm.Set(i)
+			}
+		}
+		if rnd.Intn(2) == 0 {
+			bm.ToggleAll()
+		}
+
+		for range 10 {
+			i := uint(rnd.Intn(int(n + 2)))
+
+			for _, value := range []bool{true, false} {
+				expectedIdx, expectedOk := naiveNext(bm, i, value)
+				var actualIdx uint
+				var actualOk bool
+				if value {
+					actualIdx, actualOk = bm.NextSet(i)
+				} else {
+					actualIdx, actualOk = bm.NextClear(i)
+				}
+				assert.Equal(t, expectedOk, actualOk)
+				assert.Equal(t, expectedIdx, actualIdx)
+
+				expectedIdx, expectedOk = naivePrev(bm, i, value)
+				if value {
+					actualIdx, actualOk = bm.PrevSet(i)
+				} else {
+					actualIdx, actualOk = bm.PrevClear(i)
+				}
+				assert.Equal(t, expectedOk, actualOk)
+				assert.Equal(t, expectedIdx, actualIdx)
+			}
+		}
+	}
+}
+
+func TestFirstLast(t *testing.T) {
+	tests := map[string]struct {
+		source      *BitMask
+		first, last uint
+		expectedOk  bool
+	}{
+		"empty":         {New(0), 0, 0, false},
+		"1w_clear":      {New(uintSize), 0, 0, false},
+		"1w_one":        {NewFromUint(1 << 7), 7, 7, true},
+		"2w_two":        {NewFromUint(1<<7, 1<<(uintSize-4)), 7, 2*uintSize - 4, true},
+		"3w_slice":      {NewFromUint(1, 1<<5, 1).Slice(1, 2*uintSize), uintSize + 4, uintSize + 4, true},
+		"3w_slice_tail": {NewFromUint(uintMax, 0, uintMax).Slice(uintSize-1, 2*uintSize+3), 0, uintSize + 3, true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			first, ok := tc.source.First()
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.first, first)
+
+			last, ok := tc.source.Last()
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.last, last)
+		})
+	}
+}
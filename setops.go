@@ -0,0 +1,85 @@
+package bitmask
+
+// Sets dst to the bitwise AND of a and b (intersection).
+// It's safe to use overlapping bitmasks (which were created by slicing the original one), as well as dst being one of the operands.
+// Returns the number of bits processed, which will be the minimum of dst.Len(), a.Len() and b.Len().
+// The rest of dst bits are left untouched.
+func And(dst *BitMask, a *BitMask, b *BitMask) uint {
+	return binaryOp(dst, a, b, func(x, y uint) uint { return x & y })
+}
+
+// Sets dst to the bitwise OR of a and b (union).
+// See And for the details about overlapping and lengths.
+func Or(dst *BitMask, a *BitMask, b *BitMask) uint {
+	return binaryOp(dst, a, b, func(x, y uint) uint { return x | y })
+}
+
+// Sets dst to the bitwise XOR of a and b (symmetric difference).
+// See And for the details about overlapping and lengths.
+func Xor(dst *BitMask, a *BitMask, b *BitMask) uint {
+	return binaryOp(dst, a, b, func(x, y uint) uint { return x ^ y })
+}
+
+// Sets dst to the bitwise AND NOT of a and b (difference, bits of a which are not set in b).
+// See And for the details about overlapping and lengths.
+func AndNot(dst *BitMask, a *BitMask, b *BitMask) uint {
+	return binaryOp(dst, a, b, func(x, y uint) uint { return x &^ y })
+}
+
+// Keeps only those bits, which are also set in other. Equivalent of And(bm, bm, other).
+func (bm *BitMask) And(other *BitMask) uint {
+	return And(bm, bm, other)
+}
+
+// Sets bits, which are set in other. Equivalent of Or(bm, bm, other).
+func (bm *BitMask) Or(other *BitMask) uint {
+	return Or(bm, bm, other)
+}
+
+// Toggles bits, which are set in other. Equivalent of Xor(bm, bm, other).
+func (bm *BitMask) Xor(other *BitMask) uint {
+	return Xor(bm, bm, other)
+}
+
+// Clears bits, which are set in other. Equivalent of AndNot(bm, bm, other).
+func (bm *BitMask) AndNot(other *BitMask) uint {
+	return AndNot(bm, bm, other)
+}
+
+func binaryOp(dst *BitMask, a *BitMask, b *BitMask, op func(x, y uint) uint) uint {
+	opLen := minUint(dst.len, minUint(a.len, b.len))
+	if opLen == 0 {
+		return 0
+	}
+
+	// in what direction to process words (to handle overlapping data)
+	fwdAllowed, bwdAllowed := checkDirection(dst, a)
+	fwdAllowedB, bwdAllowedB := checkDirection(dst, b)
+	if !(fwdAllowed && fwdAllowedB) && !(bwdAllowed && bwdAllowedB) {
+		// operands are overlapping dst from both sides, no direction is safe
+		b = b.clone()
+	} else {
+		fwdAllowed = fwdAllowed && fwdAllowedB
+	}
+
+	wordsN := (opLen + uintSize - 1) / uintSize
+	for i := uint(0); i < wordsN; i++ {
+		wordIndex := i
+		if !fwdAllowed {
+			wordIndex = wordsN - 1 - i
+		}
+		bitIndex := wordIndex * uintSize
+		dst.storeWord(bitIndex, minUint(uintSize, opLen-bitIndex), op(a.loadWord(bitIndex), b.loadWord(bitIndex)))
+	}
+
+	return opLen
+}
+
+// returns in which directions it's safe to process words when reading src and writing dst
+func checkDirection(dst *BitMask, src *BitMask) (fwdAllowed bool, bwdAllowed bool) {
+	if !overlaps(dst, src) {
+		return true, true
+	}
+	dstAddr, srcAddr := dst.bitAddr(), src.bitAddr()
+	return dstAddr <= srcAddr, dstAddr >= srcAddr
+}
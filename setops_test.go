@@ -0,0 +1,113 @@
+package bitmask
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomBitMask(rnd *rand.Rand, n uint) *BitMask {
+	bm := New(n)
+	for i := uint(0); i < n; i++ {
+		if rnd.Intn(2) == 0 {
+			bm.Set(i)
+		}
+	}
+	return bm
+}
+
+// Returns a random bitmask of n bits, sliced at a random offset below maxFrom from a longer random bitmask,
+// so that it's not aligned and has random neighbour bits. The longer bitmask is returned as well, to check the neighbours.
+func randomSlice(rnd *rand.Rand, maxFrom uint, n uint) (*BitMask, *BitMask) {
+	from := uint(rnd.Intn(int(maxFrom)))
+	base := randomBitMask(rnd, from+n+uint(rnd.Intn(uintSize)))
+	return base.Slice(from, from+n), base
+}
+
+// reference implementation, bit by bit
+func naiveBinaryOp(dst *BitMask, a *BitMask, b *BitMask, op func(x, y bool) bool) {
+	n := minUint(dst.Len(), minUint(a.Len(), b.Len()))
+	result := make([]bool, n)
+	for i := uint(0); i < n; i++ {
+		result[i] = op(a.IsSet(i), b.IsSet(i))
+	}
+	for i, isSet := range result {
+		if isSet {
+			dst.Set(uint(i))
+		} else {
+			dst.Clear(uint(i))
+		}
+	}
+}
+
+func TestBinaryOps(t *testing.T) {
+	ops := map[string]struct {
+		op    func(dst, a, b *BitMask) uint
+		naive func(x, y bool) bool
+	}{
+		"and":    {And, func(x, y bool) bool { return x && y }},
+		"or":     {Or, func(x, y bool) bool { return x || y }},
+		"xor":    {Xor, func(x, y bool) bool { return x != y }},
+		"andnot": {AndNot, func(x, y bool) bool { return x && !y }},
+	}
+	rnd := rand.New(rand.NewSource(1))
+
+	for name, op := range ops {
+		t.Run(name, func(t *testing.T) {
+			for range 200 {
+				n := uint(rnd.Intn(4 * uintSize))
+				base := randomBitMask(rnd, 3*n+10)
+				expectedBase := base.clone()
+				dstFrom, aFrom, bFrom := uint(rnd.Intn(int(2*n+8))), uint(rnd.Intn(int(2*n+8))), uint(rnd.Intn(int(2*n+8)))
+
+				dst, a, b := base.Slice(dstFrom, dstFrom+n), base.Slice(aFrom, aFrom+n), base.Slice(bFrom, bFrom+n)
+				expDst, expA, expB := expectedBase.Slice(dstFrom, dstFrom+n), expectedBase.Slice(aFrom, aFrom+n), expectedBase.Slice(bFrom, bFrom+n)
+
+				naiveBinaryOp(expDst, expA, expB, op.naive)
+				processed := op.op(dst, a, b)
+
+				assert.Equal(t, n, processed)
+				assert.Equal(t, bitString(expectedBase), bitString(base))
+			}
+		})
+	}
+}
+
+func TestBinaryOpsDifferentLengths(t *testing.T) {
+	dst := NewFromUint(0b1010, uintMax)
+	src := NewFromUint(0b0110).Slice(0, 3)
+
+	assert.Equal(t, uint(3), dst.Or(src))
+	assert.Equal(t, "0111"+strings.Repeat("0", uintSize-4)+strings.Repeat("1", uintSize), bitString(dst))
+
+	assert.Equal(t, uint(3), dst.AndNot(src))
+	assert.Equal(t, "0001"+strings.Repeat("0", uintSize-4)+strings.Repeat("1", uintSize), bitString(dst))
+
+	assert.Equal(t, uint(0), dst.Xor(New(0)))
+}
+
+func TestBinaryOpsOverlapping(t *testing.T) {
+	bm := New(70)
+	bm.Set(3)
+	bm.Set(66)
+
+	// shifting "down" by 3 bits, combined with the original bits
+	bm.Slice(0, 64).Or(bm.Slice(3, 67))
+
+	expected := New(70)
+	expected.Set(0)
+	expected.Set(3)
+	expected.Set(63)
+	expected.Set(66)
+	assert.Equal(t, expected.String(), bm.String())
+
+	// the same, but in the opposite direction
+	bm.Slice(3, 67).Xor(bm.Slice(0, 64))
+
+	expected.Clear(3)
+	expected.Set(6)
+	expected.Clear(66)
+	assert.Equal(t, expected.String(), bm.String())
+}
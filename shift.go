@@ -0,0 +1,65 @@
+package bitmask
+
+// Moves every bit n positions towards the end of bitmask (bit i becomes bit i+n), just like x << n does for integers,
+// keeping in mind that NewFromUint(1) has the lowest (leftmost) bit set. In String() representation bits are moving right.
+// First n bits are cleared, last n bits are lost. Works in place, so it can be combined with Slice to shift the range of bits.
+func (bm *BitMask) ShiftLeft(n uint) {
+	if n >= bm.len {
+		bm.ClearAll()
+		return
+	}
+	if n == 0 {
+		return
+	}
+	Copy(bm.Slice(n, bm.len), bm.Slice(0, bm.len-n))
+	bm.Slice(0, n).ClearAll()
+}
+
+// Moves every bit n positions towards the beginning of bitmask (bit i+n becomes bit i), just like x >> n does for integers.
+// In String() representation bits are moving left. Last n bits are cleared, first n bits are lost.
+// Works in place, so it can be combined with Slice to shift the range of bits.
+func (bm *BitMask) ShiftRight(n uint) {
+	if n >= bm.len {
+		bm.ClearAll()
+		return
+	}
+	if n == 0 {
+		return
+	}
+	Copy(bm.Slice(0, bm.len-n), bm.Slice(n, bm.len))
+	bm.Slice(bm.len-n, bm.len).ClearAll()
+}
+
+// Works like ShiftLeft, but the last n bits are becoming the first ones, instead of being lost.
+// Allocates a temporary buffer of min(n, Len()-n) bits.
+func (bm *BitMask) RotateLeft(n uint) {
+	if bm.len == 0 {
+		return
+	}
+	bm.rotateLeft(n % bm.len)
+}
+
+// Works like ShiftRight, but the first n bits are becoming the last ones, instead of being lost.
+// Allocates a temporary buffer of min(n, Len()-n) bits.
+func (bm *BitMask) RotateRight(n uint) {
+	if bm.len == 0 {
+		return
+	}
+	bm.rotateLeft((bm.len - n%bm.len) % bm.len)
+}
+
+// n must be < bm.len
+func (bm *BitMask) rotateLeft(n uint) {
+	if n == 0 {
+		return
+	}
+	if n <= bm.len-n {
+		tail := bm.Slice(bm.len-n, bm.len).clone()
+		bm.ShiftLeft(n)
+		Copy(bm, tail)
+	} else {
+		head := bm.Slice(0, bm.len-n).clone()
+		bm.ShiftRight(bm.len - n)
+		Copy(bm.Slice(n, bm.len), head)
+	}
+}
@@ -0,0 +1,75 @@
+package bitmask
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShiftRotate(t *testing.T) {
+	tests := map[string]struct {
+		op       func(bm *BitMask, n uint)
+		expected func(src *BitMask, n uint, i uint) bool
+	}{
+		"shift_left": {(*BitMask).ShiftLeft, func(src *BitMask, n uint, i uint) bool {
+			return i >= n && src.IsSet(i-n)
+		}},
+		"shift_right": {(*BitMask).ShiftRight, func(src *BitMask, n uint, i uint) bool {
+			return i+n < src.Len() && src.IsSet(i+n)
+		}},
+		"rotate_left": {(*BitMask).RotateLeft, func(src *BitMask, n uint, i uint) bool {
+			return src.IsSet((i + src.Len() - n%src.Len()) % src.Len())
+		}},
+		"rotate_right": {(*BitMask).RotateRight, func(src *BitMask, n uint, i uint) bool {
+			return src.IsSet((i + n) % src.Len())
+		}},
+	}
+	rnd := rand.New(rand.NewSource(1))
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for range 300 {
+				n := uint(1 + rnd.Intn(4*uintSize))
+				bm, base := randomSlice(rnd, 2*uintSize, n)
+				expectedBase := base.clone()
+				shift := uint(rnd.Intn(int(n + n/2)))
+
+				src := bm.clone()
+				expected := New(n)
+				for i := uint(0); i < n; i++ {
+					if tc.expected(src, shift, i) {
+						expected.Set(i)
+					} else {
+						expected.Clear(i)
+					}
+				}
+
+				tc.op(bm, shift)
+				assert.Equal(t, bitString(expected), bitString(bm))
+
+				// neighbour bits must stay untouched
+				Copy(bm, src)
+				assert.Equal(t, bitString(expectedBase), bitString(base))
+			}
+		})
+	}
+}
+
+func TestShiftExample(t *testing.T) {
+	bm := New(8)
+	bm.Set(0)
+	bm.Set(5)
+
+	bm.ShiftLeft(2)
+	assert.Equal(t, "[8]{00100001}", bm.String())
+
+	bm.RotateLeft(1)
+	assert.Equal(t, "[8]{10010000}", bm.String())
+
+	bm.Slice(2, 8).ShiftRight(1)
+	assert.Equal(t, "[8]{10100000}", bm.String())
+
+	bm.RotateRight(3)
+	assert.Equal(t, "[8]{00000101}", bm.String())
+}
@@ -0,0 +1,296 @@
+package bitmask
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Round-trippable textual representation of a bitmask, unlike String(), which skips bits.
+type TextFormat int
+
+const (
+	// "0101...", one character per bit, starting from bit 0. Default format.
+	FormatBits TextFormat = iota
+	// "hex:<length>:<bytes>", where bytes are hex-encoded bits, packed in the same way as in AppendBinary.
+	FormatHex
+	// "base64:<length>:<bytes>", where bytes are standard base64-encoded bits, packed in the same way as in AppendBinary.
+	FormatBase64
+	// "indices:<length>:<i1>,<i2>,...", list of indexes of set bits.
+	// In JSON it's encoded as an object instead: {"len":<length>,"set":[<i1>,<i2>,...]}
+	// Decoded length is limited by MaxIndicesLen.
+	FormatIndices
+)
+
+// Maximum length of a bitmask, decoded from FormatIndices. Unlike other formats, it can describe a huge bitmask with a short text,
+// so the limit protects from allocating too much memory when decoding untrusted input.
+var MaxIndicesLen uint = 1 << 24
+
+const hexPrefix = "hex:"
+const base64Prefix = "base64:"
+const indicesPrefix = "indices:"
+
+// JSON representation of FormatIndices
+type jsonIndices struct {
+	Len uint   `json:"len"`
+	Set []uint `json:"set"`
+}
+
+// Returns textual representation of a bitmask in the specified format. Unlike String(), it doesn't skip any bits.
+func (bm *BitMask) Text(format TextFormat) string {
+	return string(bm.appendText(nil, format))
+}
+
+// Implements encoding.TextMarshaler, using FormatBits.
+func (bm *BitMask) MarshalText() ([]byte, error) {
+	return bm.appendText(nil, FormatBits), nil
+}
+
+// Implements encoding.TextAppender, using FormatBits.
+func (bm *BitMask) AppendText(b []byte) ([]byte, error) {
+	return bm.appendText(b, FormatBits), nil
+}
+
+// Implements encoding.TextUnmarshaler. Accepts any TextFormat, recognizing it by prefix.
+// Replaces receiver with the decoded bitmask, so if it was a slice, it will not share the buffer with the original bitmask anymore.
+func (bm *BitMask) UnmarshalText(text []byte) error {
+	decoded, err := parseText(string(text))
+	if err != nil {
+		return err
+	}
+	*bm = *decoded
+	return nil
+}
+
+// Implements json.Marshaler, using FormatBits. Use Formatted to choose another format.
+func (bm *BitMask) MarshalJSON() ([]byte, error) {
+	return marshalJSON(bm, FormatBits)
+}
+
+// Implements json.Unmarshaler. Accepts a string in any TextFormat, or an object of FormatIndices.
+// Replaces receiver with the decoded bitmask, so if it was a slice, it will not share the buffer with the original bitmask anymore.
+func (bm *BitMask) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	decoded, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	*bm = *decoded
+	return nil
+}
+
+// Wraps a bitmask to marshal it (as text or JSON) in the specified format.
+// Unmarshaling accepts any format, allocating new BitMask.
+//
+//	type Config struct {
+//		Slots bitmask.Formatted `json:"slots"`
+//	}
+//	cfg := Config{Slots: bitmask.Formatted{BitMask: bm, Format: bitmask.FormatHex}}
+type Formatted struct {
+	BitMask *BitMask
+	Format  TextFormat
+}
+
+// Implements encoding.TextMarshaler. Nil BitMask is marshaled as empty text, which unmarshals to an empty bitmask.
+func (f Formatted) MarshalText() ([]byte, error) {
+	if f.BitMask == nil {
+		return []byte{}, nil
+	}
+	return f.BitMask.appendText(nil, f.Format), nil
+}
+
+// Implements encoding.TextUnmarshaler.
+func (f *Formatted) UnmarshalText(text []byte) error {
+	decoded, err := parseText(string(text))
+	if err != nil {
+		return err
+	}
+	f.BitMask = decoded
+	return nil
+}
+
+// Implements json.Marshaler.
+func (f Formatted) MarshalJSON() ([]byte, error) {
+	return marshalJSON(f.BitMask, f.Format)
+}
+
+// Implements json.Unmarshaler.
+func (f *Formatted) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		f.BitMask = nil
+		return nil
+	}
+	decoded, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	f.BitMask = decoded
+	return nil
+}
+
+func (bm *BitMask) appendText(b []byte, format TextFormat) []byte {
+	switch format {
+	case FormatHex:
+		b = append(b, hexPrefix...)
+		b = strconv.AppendUint(b, uint64(bm.len), 10)
+		b = append(b, ':')
+		return hex.AppendEncode(b, bm.appendBytes(nil, LSBFirst))
+	case FormatBase64:
+		b = append(b, base64Prefix...)
+		b = strconv.AppendUint(b, uint64(bm.len), 10)
+		b = append(b, ':')
+		return base64.StdEncoding.AppendEncode(b, bm.appendBytes(nil, LSBFirst))
+	case FormatIndices:
+		b = append(b, indicesPrefix...)
+		b = strconv.AppendUint(b, uint64(bm.len), 10)
+		b = append(b, ':')
+		first := true
+		for idx := range bm.SetBits() {
+			if !first {
+				b = append(b, ',')
+			}
+			b = strconv.AppendUint(b, uint64(idx), 10)
+			first = false
+		}
+		return b
+	default:
+		for i := uint(0); i < bm.len; i += uintSize {
+			w := bm.loadWord(i)
+			for j := minUint(uintSize, bm.len-i); j > 0; j-- {
+				b = append(b, '0'+byte(w>>(uintSize-1)))
+				w <<= 1
+			}
+		}
+		return b
+	}
+}
+
+func parseText(text string) (*BitMask, error) {
+	if rest, ok := strings.CutPrefix(text, hexPrefix); ok {
+		len, payload, err := parseTextLen(rest)
+		if err != nil {
+			return nil, err
+		}
+		data, err := hex.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+		}
+		return newFromTextBytes(data, len)
+	}
+	if rest, ok := strings.CutPrefix(text, base64Prefix); ok {
+		len, payload, err := parseTextLen(rest)
+		if err != nil {
+			return nil, err
+		}
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+		}
+		return newFromTextBytes(data, len)
+	}
+	if rest, ok := strings.CutPrefix(text, indicesPrefix); ok {
+		len, payload, err := parseTextLen(rest)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIndicesLen(len); err != nil {
+			return nil, err
+		}
+		bm := New(len)
+		if payload == "" {
+			return bm, nil
+		}
+		for _, s := range strings.Split(payload, ",") {
+			idx, err := strconv.ParseUint(s, 10, 0)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+			}
+			if idx >= uint64(len) {
+				return nil, fmt.Errorf("%w: index %v out of range with length %v", ErrInvalidEncoding, idx, len)
+			}
+			bm.Set(uint(idx))
+		}
+		return bm, nil
+	}
+
+	bm := New(uint(len(text)))
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '1':
+			bm.Set(uint(i))
+		case '0':
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q at %v", ErrInvalidEncoding, text[i], i)
+		}
+	}
+	return bm, nil
+}
+
+// parses "<length>:<payload>"
+func parseTextLen(text string) (uint, string, error) {
+	lenStr, payload, ok := strings.Cut(text, ":")
+	if !ok {
+		return 0, "", fmt.Errorf("%w: missing length", ErrInvalidEncoding)
+	}
+	len, err := strconv.ParseUint(lenStr, 10, 0)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	return uint(len), payload, nil
+}
+
+func newFromTextBytes(data []byte, bitsN uint) (*BitMask, error) {
+	// checking the upper bound first, so that bitsN+7 doesn't overflow
+	if bitsN > uint(len(data))*8 || uint(len(data)) != (bitsN+7)/8 {
+		return nil, fmt.Errorf("%w: expected %v bytes for %v bits, got %v", ErrInvalidEncoding, (bitsN+7)/8, bitsN, len(data))
+	}
+	return newFromBytes(data, bitsN, LSBFirst), nil
+}
+
+func marshalJSON(bm *BitMask, format TextFormat) ([]byte, error) {
+	if bm == nil {
+		return []byte("null"), nil
+	}
+	if format == FormatIndices {
+		indices := jsonIndices{Len: bm.len, Set: []uint{}}
+		for idx := range bm.SetBits() {
+			indices.Set = append(indices.Set, idx)
+		}
+		return json.Marshal(indices)
+	}
+	return json.Marshal(string(bm.appendText(nil, format)))
+}
+
+func unmarshalJSON(data []byte) (*BitMask, error) {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return parseText(text)
+	}
+	var indices jsonIndices
+	if err := json.Unmarshal(data, &indices); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	if err := checkIndicesLen(indices.Len); err != nil {
+		return nil, err
+	}
+	bm := New(indices.Len)
+	for _, idx := range indices.Set {
+		if idx >= indices.Len {
+			return nil, fmt.Errorf("%w: index %v out of range with length %v", ErrInvalidEncoding, idx, indices.Len)
+		}
+		bm.Set(idx)
+	}
+	return bm, nil
+}
+
+func checkIndicesLen(len uint) error {
+	if len > MaxIndicesLen {
+		return fmt.Errorf("%w: length %v exceeds the limit %v", ErrInvalidEncoding, len, MaxIndicesLen)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package bitmask
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextFormats(t *testing.T) {
+	bm := NewFromUint(uintMax, 0b1001_0000_0101).Slice(uintSize-2, uintSize+12)
+
+	tests := map[TextFormat]string{
+		FormatBits:    "11101000001001",
+		FormatHex:     "hex:14:1724",
+		FormatBase64:  "base64:14:FyQ=",
+		FormatIndices: "indices:14:0,1,2,4,10,13",
+	}
+	for format, expected := range tests {
+		assert.Equal(t, expected, bm.Text(format))
+
+		decoded := New(0)
+		assert.NoError(t, decoded.UnmarshalText([]byte(expected)))
+		assert.True(t, Equal(bm, decoded))
+	}
+
+	text, err := bm.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "11101000001001", string(text))
+}
+
+func TestTextEmpty(t *testing.T) {
+	for _, format := range []TextFormat{FormatBits, FormatHex, FormatBase64, FormatIndices} {
+		text := New(0).Text(format)
+		decoded := New(1)
+		assert.NoError(t, decoded.UnmarshalText([]byte(text)))
+		assert.Equal(t, uint(0), decoded.Len())
+	}
+	assert.Equal(t, "indices:5:", New(5).Text(FormatIndices))
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 100 {
+		n := uint(rnd.Intn(5 * uintSize))
+		bm := randomBitMask(rnd, n)
+		for _, format := range []TextFormat{FormatBits, FormatHex, FormatBase64, FormatIndices} {
+			decoded := New(0)
+			assert.NoError(t, decoded.UnmarshalText([]byte(bm.Text(format))))
+			assert.True(t, Equal(bm, decoded))
+		}
+	}
+}
+
+func TestTextInvalid(t *testing.T) {
+	tests := map[string]string{
+		"bad_char":            "0102",
+		"hex_no_len":          "hex:00",
+		"hex_bad_len":         "hex:x:00",
+		"hex_bad_payload":     "hex:8:zz",
+		"hex_too_short":       "hex:9:00",
+		"base64_bad_payload":  "base64:8:!",
+		"base64_too_long":     "base64:8:AAA=",
+		"indices_bad_index":   "indices:8:1,x",
+		"indices_out_of_mask": "indices:8:1,8",
+		"hex_max_len":         "hex:18446744073709551615:",
+		"base64_max_len":      "base64:18446744073709551615:",
+		"indices_max_len":     "indices:18446744073709551615:",
+		"indices_huge_len":    "indices:1000000000000:",
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			bm := New(0)
+			assert.ErrorIs(t, bm.UnmarshalText([]byte(text)), ErrInvalidEncoding)
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type config struct {
+		Default *BitMask  `json:"default"`
+		Hex     Formatted `json:"hex"`
+		Indices Formatted `json:"indices"`
+		Missing *BitMask  `json:"missing"`
+	}
+	bm := New(10)
+	bm.Set(1)
+	bm.Set(8)
+
+	data, err := json.Marshal(config{
+		Default: bm,
+		Hex:     Formatted{bm, FormatHex},
+		Indices: Formatted{bm, FormatIndices},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"default":"0100000010","hex":"hex:10:0201","indices":{"len":10,"set":[1,8]},"missing":null}`, string(data))
+
+	var decoded config
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, Equal(bm, decoded.Default))
+	assert.True(t, Equal(bm, decoded.Hex.BitMask))
+	assert.True(t, Equal(bm, decoded.Indices.BitMask))
+	assert.Nil(t, decoded.Missing)
+
+	// nil is marshaled without panicking
+	var empty Formatted
+	data, err = json.Marshal(empty)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+	text, err := empty.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(text))
+
+	// any format is accepted
+	var decodedMask BitMask
+	assert.NoError(t, json.Unmarshal([]byte(`{"len":10,"set":[1,8]}`), &decodedMask))
+	assert.True(t, Equal(bm, &decodedMask))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"len":10,"set":[10]}`), &decodedMask), ErrInvalidEncoding)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`[1]`), &decodedMask), ErrInvalidEncoding)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"len":18446744073709551615,"set":[]}`), &decodedMask), ErrInvalidEncoding)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"len":1000000000000}`), &decodedMask), ErrInvalidEncoding)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"len":1e12}`), &decodedMask), ErrInvalidEncoding)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"hex:18446744073709551615:"`), &decodedMask), ErrInvalidEncoding)
+
+	// the limit is configurable
+	defer func(limit uint) { MaxIndicesLen = limit }(MaxIndicesLen)
+	MaxIndicesLen = 1 << 25
+	assert.NoError(t, json.Unmarshal([]byte(`{"len":33554432,"set":[33554431]}`), &decodedMask))
+	assert.Equal(t, uint(1<<25), decodedMask.Len())
+	MaxIndicesLen = 9
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"len":10,"set":[1,8]}`), &decodedMask), ErrInvalidEncoding)
+}